@@ -0,0 +1,180 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrBootNotFinished is returned by [Conn.GetBootTimes] while the system is
+// still booting.
+var ErrBootNotFinished = errors.New("dbus: bootup is not yet finished")
+
+// BootTimes holds the time spent in each phase of startup, as shown by
+// systemd-analyze time. Phases that did not happen or are not known, such as
+// the firmware and loader times on systems without a boot loader reporting
+// them, or everything but userspace in containers, are zero.
+type BootTimes struct {
+	Firmware  time.Duration
+	Loader    time.Duration
+	Kernel    time.Duration
+	InitRD    time.Duration
+	Userspace time.Duration
+}
+
+// Total returns the total startup time.
+func (b *BootTimes) Total() time.Duration {
+	return b.Firmware + b.Loader + b.Kernel + b.InitRD + b.Userspace
+}
+
+// String formats the boot times like systemd-analyze time does.
+func (b *BootTimes) String() string {
+	var parts []string
+	for _, p := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{b.Firmware, "firmware"},
+		{b.Loader, "loader"},
+		{b.Kernel, "kernel"},
+		{b.InitRD, "initrd"},
+		{b.Userspace, "userspace"},
+	} {
+		if p.d > 0 {
+			parts = append(parts, p.d.Round(time.Millisecond).String()+" ("+p.name+")")
+		}
+	}
+	return "Startup finished in " + strings.Join(parts, " + ") + " = " + b.Total().Round(time.Millisecond).String()
+}
+
+// bootTimes computes the startup phases from the manager timestamps, the way
+// systemd-analyze does.
+func bootTimes(m *ManagerProperties) (*BootTimes, error) {
+	if m.FinishTimestampMonotonic == 0 {
+		return nil, ErrBootNotFinished
+	}
+
+	b := &BootTimes{}
+	// The security timestamps are only set by a system manager running
+	// directly on the kernel. Otherwise, the manager started in userspace
+	// and only the userspace phase is meaningful.
+	if m.SecurityStartTimestampMonotonic == 0 {
+		b.Userspace = m.FinishTimestampMonotonic - m.UserspaceTimestampMonotonic
+		return b, nil
+	}
+
+	// Firmware and loader timestamps count backwards from the kernel start.
+	if m.FirmwareTimestampMonotonic > m.LoaderTimestampMonotonic {
+		b.Firmware = m.FirmwareTimestampMonotonic - m.LoaderTimestampMonotonic
+	}
+	b.Loader = m.LoaderTimestampMonotonic
+	if m.InitRDTimestampMonotonic > 0 {
+		b.Kernel = m.InitRDTimestampMonotonic
+		b.InitRD = m.UserspaceTimestampMonotonic - m.InitRDTimestampMonotonic
+	} else {
+		b.Kernel = m.UserspaceTimestampMonotonic
+	}
+	b.Userspace = m.FinishTimestampMonotonic - m.UserspaceTimestampMonotonic
+
+	return b, nil
+}
+
+// GetBootTimes returns the time spent in each phase of the last startup.
+// This is the equivalent of systemd-analyze time. It returns
+// [ErrBootNotFinished] if startup is still in progress.
+func (c *Conn) GetBootTimes(ctx context.Context) (*BootTimes, error) {
+	m, err := c.GetManagerProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bootTimes(m)
+}
+
+// UnitTimes holds the activation timestamps of a unit as durations since
+// boot, as used by systemd-analyze blame.
+type UnitTimes struct {
+	Unit         string
+	Activating   time.Duration // when the unit left the inactive state
+	Activated    time.Duration // when the unit entered the active state
+	Deactivating time.Duration // when the unit left the active state
+	Deactivated  time.Duration // when the unit entered the inactive state
+	Time         time.Duration // the time the unit took to activate
+}
+
+// unitTimes returns the activation times of a unit from its properties.
+func unitTimes(p *UnitProperties) UnitTimes {
+	t := UnitTimes{
+		Unit:         p.Id,
+		Activating:   p.InactiveExitTimestampMonotonic,
+		Activated:    p.ActiveEnterTimestampMonotonic,
+		Deactivating: p.ActiveExitTimestampMonotonic,
+		Deactivated:  p.InactiveEnterTimestampMonotonic,
+	}
+	switch {
+	case t.Activating == 0:
+	case t.Activated >= t.Activating:
+		t.Time = t.Activated - t.Activating
+	case t.Deactivated >= t.Activating:
+		// The unit failed or exited before becoming active.
+		t.Time = t.Deactivated - t.Activating
+	}
+	return t
+}
+
+// blame returns the units with a non-zero activation time, slowest first.
+func blame(units []UnitTimes) []UnitTimes {
+	units = slices.DeleteFunc(units, func(t UnitTimes) bool {
+		return t.Time <= 0
+	})
+	slices.SortStableFunc(units, func(a, b UnitTimes) int {
+		return cmp.Compare(b.Time, a.Time)
+	})
+	return units
+}
+
+// GetUnitTimes returns the activation times of all loaded units.
+func (c *Conn) GetUnitTimes(ctx context.Context) ([]UnitTimes, error) {
+	units, err := c.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]UnitTimes, 0, len(units))
+	for _, u := range units {
+		p, err := c.GetTypedUnitProperties(ctx, u.Name)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, unitTimes(p))
+	}
+	return times, nil
+}
+
+// Blame returns the units that were activated, ordered by the time they took
+// to activate, slowest first. This is the equivalent of systemd-analyze
+// blame. Note that units may have been waiting for other units, so the time
+// spent is not necessarily attributable to the unit itself.
+func (c *Conn) Blame(ctx context.Context) ([]UnitTimes, error) {
+	times, err := c.GetUnitTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return blame(times), nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBootTimes(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		props ManagerProperties
+		want  BootTimes
+		total time.Duration
+		str   string
+	}{
+		{
+			name: "efi with initrd",
+			props: ManagerProperties{
+				FirmwareTimestampMonotonic:      5000 * ms,
+				LoaderTimestampMonotonic:        2000 * ms,
+				InitRDTimestampMonotonic:        1500 * ms,
+				SecurityStartTimestampMonotonic: 4000 * ms,
+				UserspaceTimestampMonotonic:     4000 * ms,
+				FinishTimestampMonotonic:        10000 * ms,
+			},
+			want:  BootTimes{Firmware: 3000 * ms, Loader: 2000 * ms, Kernel: 1500 * ms, InitRD: 2500 * ms, Userspace: 6000 * ms},
+			total: 15000 * ms,
+			str:   "Startup finished in 3s (firmware) + 2s (loader) + 1.5s (kernel) + 2.5s (initrd) + 6s (userspace) = 15s",
+		},
+		{
+			name: "no initrd",
+			props: ManagerProperties{
+				SecurityStartTimestampMonotonic: 800 * ms,
+				UserspaceTimestampMonotonic:     800 * ms,
+				FinishTimestampMonotonic:        3000 * ms,
+			},
+			want:  BootTimes{Kernel: 800 * ms, Userspace: 2200 * ms},
+			total: 3000 * ms,
+			str:   "Startup finished in 800ms (kernel) + 2.2s (userspace) = 3s",
+		},
+		{
+			name: "container",
+			props: ManagerProperties{
+				UserspaceTimestampMonotonic: 50000 * ms,
+				FinishTimestampMonotonic:    51234 * ms,
+			},
+			want:  BootTimes{Userspace: 1234 * ms},
+			total: 1234 * ms,
+			str:   "Startup finished in 1.234s (userspace) = 1.234s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bootTimes(&tt.props)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *b != tt.want {
+				t.Errorf("got %+v, want %+v", *b, tt.want)
+			}
+			if b.Total() != tt.total {
+				t.Errorf("Total() = %v, want %v", b.Total(), tt.total)
+			}
+			if b.String() != tt.str {
+				t.Errorf("String() = %q, want %q", b.String(), tt.str)
+			}
+		})
+	}
+
+	if _, err := bootTimes(&ManagerProperties{}); !errors.Is(err, ErrBootNotFinished) {
+		t.Fatalf("got %v, want %v", err, ErrBootNotFinished)
+	}
+}
+
+func TestBlame(t *testing.T) {
+	s := time.Second
+	units := []UnitTimes{
+		unitTimes(&UnitProperties{Id: "fast.service", InactiveExitTimestampMonotonic: 2 * s, ActiveEnterTimestampMonotonic: 3 * s}),
+		unitTimes(&UnitProperties{Id: "never.service"}),
+		unitTimes(&UnitProperties{Id: "slow.service", InactiveExitTimestampMonotonic: 1 * s, ActiveEnterTimestampMonotonic: 6 * s}),
+		unitTimes(&UnitProperties{Id: "failed.service", InactiveExitTimestampMonotonic: 1 * s, InactiveEnterTimestampMonotonic: 3 * s}),
+	}
+
+	got := blame(units)
+	var names []string
+	for _, u := range got {
+		names = append(names, u.Unit)
+	}
+	want := []string{"slow.service", "failed.service", "fast.service"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	if got[0].Time != 5*s || got[1].Time != 2*s || got[2].Time != s {
+		t.Fatalf("unexpected times %+v", got)
+	}
+}
+
+func TestGetBootTimes(t *testing.T) {
+	conn := setupConn(t)
+
+	m, err := conn.GetManagerProperties(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version == "" || m.NNames == 0 {
+		t.Fatalf("unexpected manager properties %+v", m)
+	}
+
+	b, err := conn.GetBootTimes(t.Context())
+	if errors.Is(err, ErrBootNotFinished) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Userspace <= 0 {
+		t.Fatalf("unexpected boot times %+v", b)
+	}
+
+	units, err := conn.Blame(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(units); i++ {
+		if units[i].Time > units[i-1].Time {
+			t.Fatalf("units are not sorted: %+v", units)
+		}
+	}
+}
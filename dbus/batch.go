@@ -0,0 +1,196 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-systemd/v22/unit"
+	"github.com/godbus/dbus/v5"
+)
+
+// DefaultBatchConcurrency is the number of units whose properties
+// [Conn.GetUnitsPropertiesBatch] reads at the same time by default.
+const DefaultBatchConcurrency = 16
+
+// BatchOptions control how [Conn.GetUnitsPropertiesBatch] reads properties.
+type BatchOptions struct {
+	// Concurrency is the maximum number of units read at the same time. If
+	// zero, DefaultBatchConcurrency is used.
+	Concurrency int
+
+	// Properties restricts reading to the named properties. They are read
+	// one by one with Properties.Get, instead of reading all properties of
+	// the interfaces with Properties.GetAll, and the calls for a unit are
+	// pipelined. Properties none of the interfaces have are left out of the
+	// result. If empty, all properties of the interfaces are returned.
+	Properties []string
+}
+
+// UnitPropertiesResult holds the properties of one unit read by
+// [Conn.GetUnitsPropertiesBatch], or the error reading them.
+type UnitPropertiesResult struct {
+	Name       string
+	Properties map[string]any
+	Err        error
+}
+
+// unitTypeInterface returns the type specific interface of a unit, e.g.
+// "Service" for foo.service. It is empty if name is not a valid unit name.
+func unitTypeInterface(name string) string {
+	n := unit.Name(name)
+	if n.Validate() != nil {
+		return ""
+	}
+	t := n.Type()
+	return strings.ToUpper(t[:1]) + t[1:]
+}
+
+// interfaceName returns the full name of a systemd D-Bus interface, which may
+// be given without the org.freedesktop.systemd1 prefix.
+func interfaceName(iface string) string {
+	if strings.Contains(iface, ".") {
+		return iface
+	}
+	return "org.freedesktop.systemd1." + iface
+}
+
+// getUnitProperties reads the properties of the given interfaces of a unit
+// and merges them. Without interfaces, the properties of the Unit interface
+// and of the type specific interface are read, like systemctl show does. If
+// names is not empty, only the named properties are read.
+func (c *Conn) getUnitProperties(ctx context.Context, name string, interfaces []string, names []string) (map[string]any, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, err
+	}
+
+	if len(interfaces) == 0 {
+		interfaces = []string{"Unit"}
+		if t := unitTypeInterface(name); t != "" {
+			interfaces = append(interfaces, t)
+		}
+	}
+	if len(names) > 0 {
+		return c.getNamedProperties(ctx, unitPath(name), interfaces, names)
+	}
+
+	out := make(map[string]any)
+	for _, iface := range interfaces {
+		props, err := c.getProperties(ctx, unitPath(name), interfaceName(iface))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range props {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// getNamedProperties reads the named properties from the given interfaces of
+// the object at path. All Properties.Get calls are sent before waiting for
+// the replies. Properties an interface does not have are skipped.
+func (c *Conn) getNamedProperties(ctx context.Context, path dbus.ObjectPath, interfaces []string, names []string) (map[string]any, error) {
+	obj := c.object(path)
+	done := make(chan *dbus.Call, len(interfaces)*len(names))
+	calls := make([]*dbus.Call, 0, cap(done))
+	for _, iface := range interfaces {
+		for _, name := range names {
+			calls = append(calls, obj.GoWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, done, interfaceName(iface), name))
+		}
+	}
+	for range calls {
+		<-done
+	}
+
+	out := make(map[string]any, len(names))
+	for i, call := range calls {
+		var v dbus.Variant
+		err := call.Store(&v)
+		var dbusErr dbus.Error
+		if errors.As(err, &dbusErr) && dbusErr.Name == "org.freedesktop.DBus.Error.UnknownProperty" {
+			continue
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		out[names[i%len(names)]] = v.Value()
+	}
+	return out, nil
+}
+
+// GetUnitsPropertiesBatch reads the properties of many units at once. The
+// Properties.GetAll calls, or Properties.Get calls if opts.Properties is set,
+// are issued concurrently over the connection, with at most
+// opts.Concurrency units in flight, instead of waiting for each unit in turn.
+//
+// interfaces are the interfaces to read, either fully qualified or without
+// the org.freedesktop.systemd1 prefix, e.g. "Unit" or "Service". Their
+// properties are merged. If interfaces is empty, the Unit interface and the
+// type specific interface of each unit are read.
+//
+// The results are in the order of units. A unit that could not be read has
+// its Err set; the other units are not affected. If ctx is done, the units
+// not yet read fail with the context error.
+func (c *Conn) GetUnitsPropertiesBatch(ctx context.Context, units []string, interfaces []string, opts BatchOptions) []UnitPropertiesResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]UnitPropertiesResult, len(units))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, unit := range units {
+		results[i].Name = unit
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Properties, results[i].Err = c.getUnitProperties(ctx, unit, interfaces, opts.Properties)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// GetUnitsPropertiesByPatterns reads the properties of all units matching
+// the given states and patterns, as listed by
+// [Conn.ListUnitsByPatternsContext]. It is the equivalent of systemctl show
+// with patterns. See [Conn.GetUnitsPropertiesBatch] for interfaces and opts.
+func (c *Conn) GetUnitsPropertiesByPatterns(ctx context.Context, states []string, patterns []string, interfaces []string, opts BatchOptions) ([]UnitPropertiesResult, error) {
+	statuses, err := c.ListUnitsByPatternsContext(ctx, states, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	units := make([]string, len(statuses))
+	for i, s := range statuses {
+		units[i] = s.Name
+	}
+	return c.GetUnitsPropertiesBatch(ctx, units, interfaces, opts), nil
+}
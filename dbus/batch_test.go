@@ -0,0 +1,37 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import "testing"
+
+func TestUnitTypeInterface(t *testing.T) {
+	for name, want := range map[string]string{
+		"foo.service":       "Service",
+		"foo@bar.service":   "Service",
+		"foo@.socket":       "Socket",
+		"home-user.mount":   "Mount",
+		"a.foo":             "",
+		"foo":               "",
+		"foo@bar.mount":     "Mount",
+		"@bar.service":      "",
+		"foo bar.service":   "",
+		"-.slice":           "Slice",
+		"var-lib.automount": "Automount",
+	} {
+		if got := unitTypeInterface(name); got != want {
+			t.Errorf("unitTypeInterface(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
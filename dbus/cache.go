@@ -0,0 +1,388 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// cacheMatches are the signals the UnitStateCache needs to receive.
+var cacheMatches = []string{
+	"type='signal',interface='org.freedesktop.systemd1.Manager',member='UnitNew'",
+	"type='signal',interface='org.freedesktop.systemd1.Manager',member='UnitRemoved'",
+	"type='signal',interface='org.freedesktop.systemd1.Manager',member='JobNew'",
+	"type='signal',interface='org.freedesktop.systemd1.Manager',member='JobRemoved'",
+	"type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',arg0='org.freedesktop.systemd1.Unit'",
+}
+
+// UnitStateChange describes a change of a unit in a [UnitStateCache].
+type UnitStateChange struct {
+	Unit string      // The unit name
+	Old  *UnitStatus // The previous state, nil if the unit is new
+	New  *UnitStatus // The current state, nil if the unit was removed
+}
+
+// UnitStateCache keeps the state of all loaded units, as returned by
+// [Conn.ListUnitsContext], up to date. It lists the units once when created,
+// and then applies the changes systemd reports in the UnitNew, UnitRemoved,
+// JobNew, JobRemoved and PropertiesChanged signals, without further method
+// calls. This is much cheaper than polling with
+// [Conn.SubscribeUnitsContext], and sees every state transition.
+//
+// Jobs that were queued after the cache was created have an empty JobType,
+// as it is not part of the signals.
+type UnitStateCache struct {
+	conn         *Conn
+	remove       func()
+	removeResync func()
+	done         chan struct{}
+
+	mu          sync.Mutex
+	seeded      bool
+	closed      bool
+	pending     []*dbus.Signal
+	units       map[string]*UnitStatus
+	subscribers map[int]chan UnitStateChange
+	nextID      int
+}
+
+// NewUnitStateCache creates a [UnitStateCache] and fills it with the units
+// currently loaded. The cache is updated until ctx is done or Close is
+// called.
+func (c *Conn) NewUnitStateCache(ctx context.Context) (*UnitStateCache, error) {
+	u := &UnitStateCache{
+		conn:        c,
+		done:        make(chan struct{}),
+		units:       make(map[string]*UnitStatus),
+		subscribers: make(map[int]chan UnitStateChange),
+	}
+
+	for _, match := range cacheMatches {
+		c.addMatch(ctx, match)
+	}
+	removeListener := c.addSignalListener(u.handle)
+	u.remove = func() {
+		removeListener()
+		for _, match := range cacheMatches {
+			c.removeMatch(match)
+		}
+	}
+
+	if err := c.subscribeSignals(ctx); err != nil {
+		u.remove()
+		return nil, err
+	}
+
+	// Signals received while listing the units are queued, and replayed on
+	// top of the listing.
+	units, err := c.ListUnitsContext(ctx)
+	if err != nil {
+		u.remove()
+		return nil, err
+	}
+	u.seed(units)
+	u.removeResync = c.addResyncHook(u.resync)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			u.Close()
+		case <-u.done:
+		}
+	}()
+
+	return u, nil
+}
+
+func (u *UnitStateCache) seed(units []UnitStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for i := range units {
+		u.units[units[i].Name] = &units[i]
+	}
+	for _, signal := range u.pending {
+		u.apply(signal)
+	}
+	u.pending = nil
+	u.seeded = true
+}
+
+// handle is called from the dispatch goroutine for every signal.
+func (u *UnitStateCache) handle(signal *dbus.Signal) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return
+	}
+	if !u.seeded {
+		u.pending = append(u.pending, signal)
+		return
+	}
+
+	if change := u.apply(signal); change != nil {
+		u.notify(*change)
+	}
+}
+
+// notify sends change to the subscribers. The caller must hold u.mu.
+func (u *UnitStateCache) notify(change UnitStateChange) {
+	for _, ch := range u.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// resync lists the units again after reconnecting, as signals may have been
+// missed, and reports the differences to the cached state.
+func (u *UnitStateCache) resync(ctx context.Context) {
+	units, err := u.conn.ListUnitsContext(ctx)
+	if err != nil {
+		return
+	}
+	u.replace(units)
+}
+
+// replace replaces the cached state with units, and reports the
+// differences.
+func (u *UnitStateCache) replace(units []UnitStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return
+	}
+	cur, changed := diffUnits(maps.Clone(u.units), units, func(u1, u2 *UnitStatus) bool {
+		return *u1 != *u2
+	}, nil)
+	for name, st := range changed {
+		change := UnitStateChange{Unit: name, Old: u.units[name]}
+		if st != nil {
+			change.New = copyStatus(st)
+		}
+		u.notify(change)
+	}
+	u.units = cur
+}
+
+// apply updates the cache from signal and returns the resulting change, or
+// nil if the signal did not change any unit. The caller must hold u.mu.
+func (u *UnitStateCache) apply(signal *dbus.Signal) *UnitStateChange {
+	switch signal.Name {
+	case "org.freedesktop.systemd1.Manager.UnitNew":
+		var name string
+		var path dbus.ObjectPath
+		if dbus.Store(signal.Body, &name, &path) != nil {
+			return nil
+		}
+		if _, ok := u.units[name]; ok {
+			return nil
+		}
+		st := &UnitStatus{Name: name, Path: path, JobPath: "/"}
+		u.units[name] = st
+		return &UnitStateChange{Unit: name, New: copyStatus(st)}
+
+	case "org.freedesktop.systemd1.Manager.UnitRemoved":
+		var name string
+		var path dbus.ObjectPath
+		if dbus.Store(signal.Body, &name, &path) != nil {
+			return nil
+		}
+		old, ok := u.units[name]
+		if !ok {
+			return nil
+		}
+		delete(u.units, name)
+		return &UnitStateChange{Unit: name, Old: old}
+
+	case "org.freedesktop.systemd1.Manager.JobNew":
+		var id uint32
+		var job dbus.ObjectPath
+		var name string
+		if dbus.Store(signal.Body, &id, &job, &name) != nil {
+			return nil
+		}
+		return u.update(name, unitPath(name), func(st *UnitStatus) {
+			st.JobId, st.JobPath, st.JobType = id, job, ""
+		})
+
+	case "org.freedesktop.systemd1.Manager.JobRemoved":
+		var id uint32
+		var job dbus.ObjectPath
+		var name, result string
+		if dbus.Store(signal.Body, &id, &job, &name, &result) != nil {
+			return nil
+		}
+		if _, ok := u.units[name]; !ok {
+			return nil
+		}
+		return u.update(name, unitPath(name), func(st *UnitStatus) {
+			if st.JobId == id {
+				st.JobId, st.JobPath, st.JobType = 0, "/", ""
+			}
+		})
+
+	case "org.freedesktop.DBus.Properties.PropertiesChanged":
+		var iface string
+		var changed map[string]dbus.Variant
+		var invalidated []string
+		if dbus.Store(signal.Body, &iface, &changed, &invalidated) != nil || iface != "org.freedesktop.systemd1.Unit" {
+			return nil
+		}
+		name := unitName(signal.Path)
+		if id, ok := changed["Id"].Value().(string); ok {
+			name = id
+		}
+		return u.update(name, signal.Path, func(st *UnitStatus) {
+			applyUnitProperties(st, changed)
+		})
+	}
+
+	return nil
+}
+
+// update applies f to the cached state of the unit name, adding the unit if
+// it is not known yet. The caller must hold u.mu.
+func (u *UnitStateCache) update(name string, path dbus.ObjectPath, f func(*UnitStatus)) *UnitStateChange {
+	old, ok := u.units[name]
+	st := &UnitStatus{Name: name, Path: path, JobPath: "/"}
+	if ok {
+		st = copyStatus(old)
+	}
+	f(st)
+	if ok && *st == *old {
+		return nil
+	}
+	u.units[name] = st
+	return &UnitStateChange{Unit: name, Old: old, New: copyStatus(st)}
+}
+
+// applyUnitProperties updates st with the changed properties of the
+// org.freedesktop.systemd1.Unit interface.
+func applyUnitProperties(st *UnitStatus, changed map[string]dbus.Variant) {
+	for k, v := range changed {
+		switch k {
+		case "Description":
+			st.Description, _ = v.Value().(string)
+		case "LoadState":
+			st.LoadState, _ = v.Value().(string)
+		case "ActiveState":
+			st.ActiveState, _ = v.Value().(string)
+		case "SubState":
+			st.SubState, _ = v.Value().(string)
+		case "Following":
+			st.Followed, _ = v.Value().(string)
+		case "Job":
+			var job struct {
+				Id   uint32
+				Path dbus.ObjectPath
+			}
+			if dbus.Store([]any{v.Value()}, &job) == nil && job.Id != st.JobId {
+				st.JobId, st.JobPath, st.JobType = job.Id, job.Path, ""
+			}
+		}
+	}
+}
+
+func copyStatus(st *UnitStatus) *UnitStatus {
+	c := *st
+	return &c
+}
+
+// Snapshot returns a copy of the state of all units in the cache, keyed by
+// unit name.
+func (u *UnitStateCache) Snapshot() map[string]UnitStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	snapshot := make(map[string]UnitStatus, len(u.units))
+	for name, st := range u.units {
+		snapshot[name] = *st
+	}
+	return snapshot
+}
+
+// Get returns the state of the unit name, and whether it is in the cache.
+func (u *UnitStateCache) Get(name string) (UnitStatus, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	st, ok := u.units[name]
+	if !ok {
+		return UnitStatus{}, false
+	}
+	return *st, true
+}
+
+// Changes returns a channel receiving every change to the cache, with the
+// given buffer size. Changes are sent without blocking the cache, so they
+// are dropped if the buffer is full; use [UnitStateCache.Snapshot] to
+// resynchronize. The returned function stops the stream and closes the
+// channel. The channel is also closed when the cache is closed.
+func (u *UnitStateCache) Changes(buffer int) (<-chan UnitStateChange, func()) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ch := make(chan UnitStateChange, buffer)
+	if u.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	id := u.nextID
+	u.nextID++
+	u.subscribers[id] = ch
+
+	return ch, func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if _, ok := u.subscribers[id]; ok {
+			delete(u.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// Close stops updating the cache and closes all change streams. The last
+// state stays available through Snapshot and Get.
+func (u *UnitStateCache) Close() {
+	u.mu.Lock()
+	if u.closed {
+		u.mu.Unlock()
+		return
+	}
+	u.closed = true
+	close(u.done)
+	subscribers := u.subscribers
+	u.subscribers = make(map[int]chan UnitStateChange)
+	u.mu.Unlock()
+
+	// Removing the listener waits for a running handle call, which needs
+	// u.mu, so it has to happen without holding it.
+	u.remove()
+	if u.removeResync != nil {
+		u.removeResync()
+	}
+	for ch := range maps.Values(subscribers) {
+		close(ch)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func newTestCache() *UnitStateCache {
+	return &UnitStateCache{
+		remove:      func() {},
+		done:        make(chan struct{}),
+		units:       make(map[string]*UnitStatus),
+		subscribers: make(map[int]chan UnitStateChange),
+	}
+}
+
+func propertiesChangedSignal(unit string, changed map[string]any) *dbus.Signal {
+	props := make(map[string]dbus.Variant, len(changed))
+	for k, v := range changed {
+		props[k] = dbus.MakeVariant(v)
+	}
+	return &dbus.Signal{
+		Path: unitPath(unit),
+		Name: "org.freedesktop.DBus.Properties.PropertiesChanged",
+		Body: []any{"org.freedesktop.systemd1.Unit", props, []string{}},
+	}
+}
+
+func managerSignal(member string, body ...any) *dbus.Signal {
+	return &dbus.Signal{
+		Path: "/org/freedesktop/systemd1",
+		Name: "org.freedesktop.systemd1.Manager." + member,
+		Body: body,
+	}
+}
+
+func TestUnitStateCache(t *testing.T) {
+	u := newTestCache()
+
+	// Signals received before seeding are replayed on top of the listing.
+	u.handle(propertiesChangedSignal("a.service", map[string]any{"ActiveState": "activating", "SubState": "start"}))
+	u.seed([]UnitStatus{
+		{Name: "a.service", LoadState: "loaded", ActiveState: "inactive", SubState: "dead", Path: unitPath("a.service"), JobPath: "/"},
+		{Name: "b.service", LoadState: "loaded", ActiveState: "active", SubState: "running", Path: unitPath("b.service"), JobPath: "/"},
+	})
+	if st, _ := u.Get("a.service"); st.ActiveState != "activating" || st.LoadState != "loaded" {
+		t.Fatalf("unexpected state after seeding %+v", st)
+	}
+
+	changes, stop := u.Changes(10)
+	defer stop()
+
+	u.handle(propertiesChangedSignal("a.service", map[string]any{"ActiveState": "active", "SubState": "running"}))
+	// Unchanged properties don't produce a change.
+	u.handle(propertiesChangedSignal("a.service", map[string]any{"ActiveState": "active"}))
+	u.handle(managerSignal("JobNew", uint32(7), dbus.ObjectPath("/org/freedesktop/systemd1/job/7"), "b.service"))
+	u.handle(managerSignal("JobRemoved", uint32(7), dbus.ObjectPath("/org/freedesktop/systemd1/job/7"), "b.service", "done"))
+	u.handle(managerSignal("UnitNew", "c.service", unitPath("c.service")))
+	u.handle(managerSignal("UnitRemoved", "b.service", unitPath("b.service")))
+
+	want := []struct {
+		unit    string
+		old     bool
+		new     bool
+		active  string
+		jobID   uint32
+		removed bool
+	}{
+		{unit: "a.service", old: true, new: true, active: "active"},
+		{unit: "b.service", old: true, new: true, active: "active", jobID: 7},
+		{unit: "b.service", old: true, new: true, active: "active"},
+		{unit: "c.service", new: true},
+		{unit: "b.service", old: true},
+	}
+	for _, w := range want {
+		var c UnitStateChange
+		select {
+		case c = <-changes:
+		case <-time.After(time.Second):
+			t.Fatalf("missing change for %s", w.unit)
+		}
+		if c.Unit != w.unit || (c.Old != nil) != w.old || (c.New != nil) != w.new {
+			t.Fatalf("unexpected change %+v, want %+v", c, w)
+		}
+		if c.New != nil && (c.New.ActiveState != w.active || c.New.JobId != w.jobID) {
+			t.Fatalf("unexpected new state %+v, want %+v", c.New, w)
+		}
+	}
+	select {
+	case c := <-changes:
+		t.Fatalf("unexpected change %+v", c)
+	default:
+	}
+
+	snapshot := u.Snapshot()
+	if len(snapshot) != 2 || snapshot["a.service"].SubState != "running" {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if _, ok := u.Get("b.service"); ok {
+		t.Fatal("b.service should have been removed")
+	}
+
+	u.Close()
+	if _, ok := <-changes; ok {
+		t.Fatal("change stream should be closed")
+	}
+	u.handle(propertiesChangedSignal("a.service", map[string]any{"ActiveState": "failed"}))
+	if st, _ := u.Get("a.service"); st.ActiveState != "active" {
+		t.Fatal("closed cache should not be updated")
+	}
+}
+
+func TestNewUnitStateCache(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	cache, err := conn.NewUnitStateCache(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	changes, stop := cache.Changes(100)
+	defer stop()
+
+	job, err := conn.StartUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(t.Context(), target, "replace", nil)
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case c := <-changes:
+			if c.Unit == target && c.New != nil && c.New.ActiveState == "active" {
+				if st, _ := cache.Get(target); st.SubState != "running" {
+					t.Fatalf("unexpected cached state %+v", st)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("did not see unit becoming active")
+		}
+	}
+}
+
+func benchmarkUnits(n int) ([]UnitStatus, []any) {
+	units := make([]UnitStatus, n)
+	reply := make([]any, n)
+	for i := range units {
+		name := fmt.Sprintf("unit-%d.service", i)
+		units[i] = UnitStatus{
+			Name: name, Description: name, LoadState: "loaded", ActiveState: "active", SubState: "running",
+			Path: unitPath(name), JobPath: "/",
+		}
+		reply[i] = []any{name, name, "loaded", "active", "running", "", unitPath(name), uint32(0), "", dbus.ObjectPath("/")}
+	}
+	return units, reply
+}
+
+// BenchmarkPollUnits measures the work SubscribeUnitsContext does on every
+// tick to detect a state change: decoding the ListUnits reply and diffing
+// it against the previous listing.
+func BenchmarkPollUnits(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			_, reply := benchmarkUnits(n)
+			units, _ := convertSlice[UnitStatus](reply)
+			old, _ := diffUnits(make(map[string]*UnitStatus), units, mismatchUnitStatus, nil)
+
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				reply[0].([]any)[4] = fmt.Sprint(i)
+				units, err := convertSlice[UnitStatus](reply)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var changed map[string]*UnitStatus
+				old, changed = diffUnits(old, units, mismatchUnitStatus, nil)
+				if len(changed) != 1 {
+					b.Fatalf("got %d changes", len(changed))
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkUnitStateCache measures the work the UnitStateCache does to
+// apply a state change from a PropertiesChanged signal.
+func BenchmarkUnitStateCache(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			units, _ := benchmarkUnits(n)
+			u := newTestCache()
+			u.seed(units)
+			changes, stop := u.Changes(1)
+			defer stop()
+
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				u.handle(propertiesChangedSignal("unit-0.service", map[string]any{"SubState": fmt.Sprint(i)}))
+				<-changes
+			}
+		})
+	}
+}
+
+func TestUnitStateCacheReplace(t *testing.T) {
+	u := newTestCache()
+	u.seed([]UnitStatus{
+		{Name: "a.service", ActiveState: "active", JobPath: "/"},
+		{Name: "b.service", ActiveState: "active", JobPath: "/"},
+		{Name: "c.service", ActiveState: "active", JobPath: "/"},
+	})
+	ch, stop := u.Changes(10)
+	defer stop()
+
+	// After reconnecting, b.service stopped, c.service was removed and
+	// d.service was added.
+	u.replace([]UnitStatus{
+		{Name: "a.service", ActiveState: "active", JobPath: "/"},
+		{Name: "b.service", ActiveState: "inactive", JobPath: "/"},
+		{Name: "d.service", ActiveState: "active", JobPath: "/"},
+	})
+
+	got := make(map[string]UnitStateChange)
+	for len(ch) > 0 {
+		change := <-ch
+		got[change.Unit] = change
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d changes, want 3: %v", len(got), got)
+	}
+	if b := got["b.service"]; b.Old.ActiveState != "active" || b.New.ActiveState != "inactive" {
+		t.Errorf("unexpected change of b.service: %+v -> %+v", b.Old, b.New)
+	}
+	if c := got["c.service"]; c.Old == nil || c.New != nil {
+		t.Errorf("c.service not reported as removed")
+	}
+	if d := got["d.service"]; d.Old != nil || d.New == nil {
+		t.Errorf("d.service not reported as new")
+	}
+
+	if _, ok := u.Get("c.service"); ok {
+		t.Error("removed unit still cached")
+	}
+	if st, _ := u.Get("b.service"); st.ActiveState != "inactive" {
+		t.Errorf("b.service ActiveState = %q, want inactive", st.ActiveState)
+	}
+}
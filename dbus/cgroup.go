@@ -0,0 +1,240 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// UnitProcess is a process running in the control group of a unit.
+type UnitProcess struct {
+	Path    string // The control group path, relative to the cgroupfs root
+	PID     uint32 // The process ID
+	Command string // The command line, or the process name in brackets for kernel threads
+}
+
+// GetUnitProcesses returns the processes of a unit and of its sub control
+// groups. For slices, the processes of all units in the slice are returned.
+func (c *Conn) GetUnitProcesses(ctx context.Context, name string) ([]UnitProcess, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, err
+	}
+	return storeSlice[UnitProcess](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetUnitProcesses", 0, name).Store)
+}
+
+// CGroupOptions control where [ReadCGroupTree] reads control groups and
+// processes from.
+type CGroupOptions struct {
+	// Root is the mount point of the cgroup hierarchy managed by systemd.
+	// If empty, /sys/fs/cgroup is used on systems with the unified
+	// hierarchy, and /sys/fs/cgroup/systemd otherwise.
+	Root string
+
+	// ProcRoot is the mount point of procfs, from which command lines are
+	// read. If empty, /proc is used.
+	ProcRoot string
+}
+
+func (o CGroupOptions) root() string {
+	if o.Root != "" {
+		return o.Root
+	}
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.procs"); err != nil {
+		return "/sys/fs/cgroup/systemd"
+	}
+	return "/sys/fs/cgroup"
+}
+
+func (o CGroupOptions) procRoot() string {
+	if o.ProcRoot != "" {
+		return o.ProcRoot
+	}
+	return "/proc"
+}
+
+// CGroup is a control group, together with its processes and sub control
+// groups.
+type CGroup struct {
+	Path      string        // The path relative to the cgroupfs root, e.g. /system.slice/foo.service
+	Unit      string        // The unit the control group belongs to, empty for the root
+	Processes []UnitProcess // The processes in this control group only
+	Children  []*CGroup
+}
+
+// cgroupUnit returns the unit the control group p belongs to: the innermost
+// path element that is a unit name. The processes of user units are thus
+// attributed to the user unit, not to the user@.service of the user manager.
+func cgroupUnit(p string) string {
+	var u string
+	for _, e := range strings.Split(p, "/") {
+		if unit.Name(e).Validate() == nil {
+			u = e
+		}
+	}
+	return u
+}
+
+// ReadCGroupTree reads the control group hierarchy below p, e.g.
+// /system.slice, and attributes every process to its unit, like systemd-cgls
+// does. The hierarchy is read from cgroupfs directly; processes and control
+// groups that disappear while it is read are skipped.
+func ReadCGroupTree(p string, opts CGroupOptions) (*CGroup, error) {
+	p = path.Clean("/" + p)
+	return readCGroup(opts.root(), opts.procRoot(), p, cgroupUnit(p))
+}
+
+func readCGroup(root, procRoot, p, parentUnit string) (*CGroup, error) {
+	dir := filepath.Join(root, p)
+	procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+
+	cg := &CGroup{Path: p, Unit: parentUnit}
+	if unit.Name(path.Base(p)).Validate() == nil {
+		cg.Unit = path.Base(p)
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(procs))
+	for s.Scan() {
+		pid, err := strconv.ParseUint(strings.TrimSpace(s.Text()), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid PID %q", dir, s.Text())
+		}
+		cg.Processes = append(cg.Processes, UnitProcess{
+			Path:    p,
+			PID:     uint32(pid),
+			Command: processCommand(procRoot, uint32(pid)),
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		child, err := readCGroup(root, procRoot, path.Join(p, e.Name()), cg.Unit)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cg.Children = append(cg.Children, child)
+	}
+
+	return cg, nil
+}
+
+// processCommand returns the command line of a process, or its name in
+// brackets if the command line is empty, as for kernel threads. It returns
+// an empty string if the process is gone.
+func processCommand(procRoot string, pid uint32) string {
+	dir := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10))
+	cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return ""
+	}
+	if cmdline = bytes.TrimRight(cmdline, "\x00"); len(cmdline) > 0 {
+		return string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))
+	}
+
+	comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return ""
+	}
+	return "[" + strings.TrimSpace(string(comm)) + "]"
+}
+
+// GetUnitCGroupTree reads the control group hierarchy of a unit, as
+// [ReadCGroupTree] does. The unit must be active.
+func (c *Conn) GetUnitCGroupTree(ctx context.Context, name string, opts CGroupOptions) (*CGroup, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, err
+	}
+	p, err := GetProperty[string](ctx, c, name, unitTypeInterface(name), "ControlGroup")
+	if err != nil {
+		return nil, err
+	}
+	if p == "" {
+		return nil, fmt.Errorf("unit %s has no control group", name)
+	}
+	return ReadCGroupTree(p, opts)
+}
+
+// Walk calls fn for cg and all control groups below it, parents before
+// their children.
+func (cg *CGroup) Walk(fn func(*CGroup)) {
+	fn(cg)
+	for _, child := range cg.Children {
+		child.Walk(fn)
+	}
+}
+
+// UnitProcesses returns the processes below cg by the unit they belong to.
+func (cg *CGroup) UnitProcesses() map[string][]UnitProcess {
+	units := make(map[string][]UnitProcess)
+	cg.Walk(func(cg *CGroup) {
+		units[cg.Unit] = append(units[cg.Unit], cg.Processes...)
+	})
+	return units
+}
+
+// String formats the control group tree like systemd-cgls.
+func (cg *CGroup) String() string {
+	var b strings.Builder
+	b.WriteString(cg.Path)
+	b.WriteByte('\n')
+	cg.format(&b, "")
+	return b.String()
+}
+
+func (cg *CGroup) format(b *strings.Builder, prefix string) {
+	n := len(cg.Processes) + len(cg.Children)
+	for i, p := range cg.Processes {
+		if i == n-1 {
+			b.WriteString(prefix + "└─")
+		} else {
+			b.WriteString(prefix + "├─")
+		}
+		fmt.Fprintf(b, "%d %s\n", p.PID, p.Command)
+	}
+	for i, child := range cg.Children {
+		childPrefix := prefix + "│ "
+		if len(cg.Processes)+i == n-1 {
+			b.WriteString(prefix + "└─")
+			childPrefix = prefix + "  "
+		} else {
+			b.WriteString(prefix + "├─")
+		}
+		b.WriteString(path.Base(child.Path))
+		b.WriteByte('\n')
+		child.format(b, childPrefix)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates the given files below dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadCGroupTree(t *testing.T) {
+	root := t.TempDir()
+	proc := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"system.slice/cgroup.procs":                                 "",
+		"system.slice/foo.service/cgroup.procs":                     "10\n11\n",
+		"system.slice/bar.service/cgroup.procs":                     "",
+		"system.slice/bar.service/payload/cgroup.procs":             "20\n",
+		"system.slice/bar.service/payload/memory.max":               "max\n",
+		"user.slice/user-1000.slice/user@1000.service/cgroup.procs": "",
+	})
+	writeFiles(t, proc, map[string]string{
+		"10/cmdline": "/usr/bin/foo\x00--verbose\x00",
+		"11/cmdline": "",
+		"11/comm":    "kworker\n",
+		"20/cmdline": "/usr/bin/bar\x00",
+	})
+
+	cg, err := ReadCGroupTree("/system.slice", CGroupOptions{Root: root, ProcRoot: proc})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]UnitProcess{
+		"system.slice": nil,
+		"bar.service":  {{Path: "/system.slice/bar.service/payload", PID: 20, Command: "/usr/bin/bar"}},
+		"foo.service": {
+			{Path: "/system.slice/foo.service", PID: 10, Command: "/usr/bin/foo --verbose"},
+			{Path: "/system.slice/foo.service", PID: 11, Command: "[kworker]"},
+		},
+	}
+	if got := cg.UnitProcesses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnitProcesses() = %v, want %v", got, want)
+	}
+
+	wantTree := `/system.slice
+├─bar.service
+│ └─payload
+│   └─20 /usr/bin/bar
+└─foo.service
+  ├─10 /usr/bin/foo --verbose
+  └─11 [kworker]
+`
+	if got := cg.String(); got != wantTree {
+		t.Errorf("String() = \n%s, want\n%s", got, wantTree)
+	}
+
+	if _, err := ReadCGroupTree("/missing.slice", CGroupOptions{Root: root, ProcRoot: proc}); err == nil {
+		t.Error("reading a missing control group succeeded")
+	}
+}
+
+func TestCGroupUnit(t *testing.T) {
+	tests := map[string]string{
+		"/":                                 "",
+		"/system.slice/foo.service/payload": "foo.service",
+		"/user.slice/user-1000.slice/user@1000.service/app.slice/bar.service": "bar.service",
+		"/init.scope": "init.scope",
+	}
+	for p, want := range tests {
+		if got := cgroupUnit(p); got != want {
+			t.Errorf("cgroupUnit(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// SIGCHLD codes reported by systemd in ExecMainCode.
+const (
+	cldExited = 1
+	cldKilled = 2
+	cldDumped = 3
+)
+
+// Command is a command run as a transient service, similar to an
+// os/exec.Cmd. This is the equivalent of systemd-run --wait --pipe: the
+// command gets its own unit with cgroup accounting, while its standard
+// streams are connected to the calling process.
+//
+// A Command is created with [Conn.Command] and cannot be reused after
+// calling its Run or Wait methods.
+type Command struct {
+	// Path is the path of the command to run. It must be absolute, as it is
+	// executed by systemd, not by the calling process.
+	Path string
+
+	// Args holds the command line arguments, including the command as
+	// Args[0].
+	Args []string
+
+	// Env specifies the environment of the process, each entry of the form
+	// "key=value". Unlike with os/exec, the environment of the calling
+	// process is not inherited; systemd sets up its usual service
+	// environment instead.
+	Env []string
+
+	// Dir specifies the working directory of the command. If empty, systemd
+	// uses its default, the root directory.
+	Dir string
+
+	// Stdin, Stdout and Stderr specify the standard streams of the process.
+	// If one is an *os.File, its file descriptor is passed to systemd
+	// directly. Otherwise a pipe is created and data is copied in a
+	// goroutine, like os/exec does. If nil, the unit's default is used,
+	// which is /dev/null for input and the journal for output.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Unit is the name of the transient service. If empty, Start sets it to
+	// a randomly generated name.
+	Unit string
+
+	// Properties holds additional properties of the transient service,
+	// such as resource limits.
+	Properties []Property
+
+	conn    *Conn
+	lookErr error
+
+	started  bool
+	finished bool
+
+	closeAfterStart []io.Closer
+	closeAfterWait  []io.Closer // Our write ends, closed once the service is gone
+	closeAfterCopy  []io.Closer // Our read ends, closed once copying is done
+	goroutines      []func() error
+	goroutineErr    chan error
+
+	changed   <-chan struct{}
+	stopWatch func()
+}
+
+// ExitError is returned by [Command.Wait] if the service did not finish
+// successfully.
+type ExitError struct {
+	Unit   string // the name of the transient service
+	Result string // the service result, e.g. exit-code, signal or timeout
+	Code   int32  // the SIGCHLD code of the main process (CLD_EXITED, CLD_KILLED, ...)
+	Status int32  // the exit status or the signal number of the main process
+}
+
+func (e *ExitError) Error() string {
+	switch e.Code {
+	case cldExited:
+		return fmt.Sprintf("unit %s: exit status %d", e.Unit, e.Status)
+	case cldKilled, cldDumped:
+		return fmt.Sprintf("unit %s: signal: %s", e.Unit, syscall.Signal(e.Status))
+	default:
+		return fmt.Sprintf("unit %s: %s", e.Unit, e.Result)
+	}
+}
+
+// ExitCode returns the exit code of the main process, or -1 if it did not
+// exit normally.
+func (e *ExitError) ExitCode() int {
+	if e.Code != cldExited {
+		return -1
+	}
+	return int(e.Status)
+}
+
+// Command returns a [Command] to run the named program with the given
+// arguments as a transient service. If name contains no path separators, it
+// is resolved with exec.LookPath in the calling process.
+func (c *Conn) Command(name string, arg ...string) *Command {
+	cmd := &Command{
+		Path: name,
+		Args: append([]string{name}, arg...),
+		conn: c,
+	}
+	if !strings.Contains(name, "/") {
+		lp, err := exec.LookPath(name)
+		if err != nil {
+			cmd.lookErr = err
+		} else {
+			cmd.Path = lp
+		}
+	}
+	return cmd
+}
+
+// String returns a human-readable description of the command.
+func (cmd *Command) String() string {
+	b := new(strings.Builder)
+	b.WriteString(cmd.Path)
+	for _, a := range cmd.Args[1:] {
+		b.WriteByte(' ')
+		b.WriteString(a)
+	}
+	return b.String()
+}
+
+func (cmd *Command) closeDescriptors(closers []io.Closer) {
+	for _, fd := range closers {
+		fd.Close()
+	}
+}
+
+func (cmd *Command) stdin() (*os.File, error) {
+	if f, ok := cmd.Stdin.(*os.File); ok {
+		return f, nil
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.closeAfterStart = append(cmd.closeAfterStart, pr)
+	cmd.closeAfterWait = append(cmd.closeAfterWait, pw)
+	cmd.goroutines = append(cmd.goroutines, func() error {
+		_, err := io.Copy(pw, cmd.Stdin)
+		// The service may exit without reading all of its input.
+		if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+			err = nil
+		}
+		if err1 := pw.Close(); err == nil && !errors.Is(err1, os.ErrClosed) {
+			err = err1
+		}
+		return err
+	})
+	return pr, nil
+}
+
+func (cmd *Command) writer(w io.Writer) (*os.File, error) {
+	if f, ok := w.(*os.File); ok {
+		return f, nil
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.closeAfterStart = append(cmd.closeAfterStart, pw)
+	cmd.closeAfterCopy = append(cmd.closeAfterCopy, pr)
+	cmd.goroutines = append(cmd.goroutines, func() error {
+		_, err := io.Copy(w, pr)
+		pr.Close()
+		return err
+	})
+	return pw, nil
+}
+
+// interfaceEqual protects against panics from doing equality tests on two
+// interfaces with non-comparable underlying types.
+func interfaceEqual(a, b any) bool {
+	defer func() {
+		_ = recover()
+	}()
+	return a == b
+}
+
+// properties returns the properties of the transient service.
+func (cmd *Command) properties() ([]Property, []*os.File, error) {
+	props := []Property{
+		PropDescription(cmd.String()),
+		{
+			Name:  "ExecStart",
+			Value: dbus.MakeVariant([]execStart{{Path: cmd.Path, Args: cmd.Args}}),
+		},
+		// Keep the unit around until Wait has read its exit status.
+		{Name: "AddRef", Value: dbus.MakeVariant(true)},
+		{Name: "CollectMode", Value: dbus.MakeVariant("inactive-or-failed")},
+	}
+
+	if len(cmd.Env) > 0 {
+		p, err := PropEnvironment(cmd.Env...)
+		if err != nil {
+			return nil, nil, err
+		}
+		props = append(props, p)
+	}
+	if cmd.Dir != "" {
+		p, err := PropWorkingDirectory(cmd.Dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		props = append(props, p)
+	}
+
+	var files []*os.File
+	addFD := func(name string, f *os.File) {
+		files = append(files, f)
+		props = append(props, Property{
+			Name:  name,
+			Value: dbus.MakeVariant(dbus.UnixFD(f.Fd())),
+		})
+	}
+
+	if cmd.Stdin != nil {
+		f, err := cmd.stdin()
+		if err != nil {
+			return nil, nil, err
+		}
+		addFD("StandardInputFileDescriptor", f)
+	}
+	var stdout *os.File
+	if cmd.Stdout != nil {
+		f, err := cmd.writer(cmd.Stdout)
+		if err != nil {
+			return nil, nil, err
+		}
+		stdout = f
+		addFD("StandardOutputFileDescriptor", f)
+	}
+	if cmd.Stderr != nil {
+		f := stdout
+		if f == nil || !interfaceEqual(cmd.Stderr, cmd.Stdout) {
+			var err error
+			if f, err = cmd.writer(cmd.Stderr); err != nil {
+				return nil, nil, err
+			}
+		}
+		addFD("StandardErrorFileDescriptor", f)
+	}
+
+	return append(props, cmd.Properties...), files, nil
+}
+
+// Start starts the command as a transient service and waits for the start
+// job to complete. If the job does not finish successfully, the returned
+// error wraps its [JobResult].
+func (cmd *Command) Start(ctx context.Context) error {
+	if cmd.conn == nil {
+		return errors.New("dbus: Command not created by Conn.Command")
+	}
+	if cmd.lookErr != nil {
+		return cmd.lookErr
+	}
+	if cmd.started {
+		return errors.New("dbus: already started")
+	}
+	if !strings.HasPrefix(cmd.Path, "/") {
+		return fmt.Errorf("dbus: command path must be absolute, got %q", cmd.Path)
+	}
+	cmd.started = true
+
+	if cmd.Unit == "" {
+		cmd.Unit = transientName(".service")
+	}
+
+	props, files, err := cmd.properties()
+	if err != nil {
+		cmd.closeDescriptors(cmd.closeAfterStart)
+		cmd.closeDescriptors(cmd.closeAfterWait)
+		cmd.closeDescriptors(cmd.closeAfterCopy)
+		return err
+	}
+
+	path := unitPath(cmd.Unit)
+	cmd.changed, cmd.stopWatch, err = cmd.conn.watchUnitProperties(ctx, path)
+	if err != nil {
+		cmd.closeDescriptors(cmd.closeAfterStart)
+		cmd.closeDescriptors(cmd.closeAfterWait)
+		cmd.closeDescriptors(cmd.closeAfterCopy)
+		return err
+	}
+
+	job, err := cmd.conn.StartTransientUnitJob(ctx, cmd.Unit, "fail", props, nil)
+	runtime.KeepAlive(files)
+	cmd.closeDescriptors(cmd.closeAfterStart)
+	if err != nil {
+		cmd.stopWatch()
+		cmd.closeDescriptors(cmd.closeAfterWait)
+		cmd.closeDescriptors(cmd.closeAfterCopy)
+		return err
+	}
+
+	cmd.startCopying()
+
+	if err := job.Wait(ctx); err != nil {
+		cmd.finished = true
+		cmd.abort()
+		return fmt.Errorf("dbus: starting %s: %w", cmd.Unit, err)
+	}
+
+	return nil
+}
+
+// startCopying starts the goroutines copying to and from the standard
+// streams.
+func (cmd *Command) startCopying() {
+	cmd.goroutineErr = make(chan error, len(cmd.goroutines))
+	for _, fn := range cmd.goroutines {
+		go func() {
+			cmd.goroutineErr <- fn()
+		}()
+	}
+}
+
+// waitCopying waits for the copying goroutines to finish, then closes our
+// read ends of the pipes. It returns the first copying error.
+func (cmd *Command) waitCopying() error {
+	var copyError error
+	for range cmd.goroutines {
+		if err := <-cmd.goroutineErr; err != nil && copyError == nil {
+			copyError = err
+		}
+	}
+	cmd.closeDescriptors(cmd.closeAfterCopy)
+	return copyError
+}
+
+// release drops the reference to the unit and closes our write ends of the
+// pipes. The read ends stay open, so that the output still buffered in the
+// pipes is copied.
+func (cmd *Command) release() {
+	cmd.stopWatch()
+	obj := cmd.conn.object(unitPath(cmd.Unit))
+	_ = obj.Call("org.freedesktop.systemd1.Unit.Unref", 0).Store()
+	cmd.closeDescriptors(cmd.closeAfterWait)
+}
+
+// abort releases the unit and closes all remaining descriptors without
+// waiting for the copying goroutines, which then fail.
+func (cmd *Command) abort() {
+	cmd.release()
+	cmd.closeDescriptors(cmd.closeAfterCopy)
+}
+
+// Wait waits for the service to become inactive and for any copying to or
+// from its standard streams to complete.
+//
+// The returned error is nil if the service finished successfully, an
+// [*ExitError] if it did not, or an error from copying the standard streams.
+// If ctx is done before the service finishes, the service is stopped and the
+// context error is returned.
+func (cmd *Command) Wait(ctx context.Context) error {
+	if !cmd.started {
+		return errors.New("dbus: not started")
+	}
+	if cmd.finished {
+		return errors.New("dbus: Wait was already called")
+	}
+	cmd.finished = true
+
+	for {
+		state, err := GetProperty[string](ctx, cmd.conn, cmd.Unit, "Unit", "ActiveState")
+		if err != nil {
+			cmd.abort()
+			return err
+		}
+		if state == "inactive" || state == "failed" {
+			break
+		}
+
+		select {
+		case <-cmd.changed:
+		case <-ctx.Done():
+			_, _ = cmd.conn.StopUnitContext(context.WithoutCancel(ctx), cmd.Unit, "replace", nil)
+			cmd.abort()
+			return ctx.Err()
+		}
+	}
+
+	service, err := cmd.conn.GetTypedServiceProperties(ctx, cmd.Unit)
+	// Releasing the unit lets systemd close its copies of the pipes, which
+	// the copying goroutines need to finish.
+	cmd.release()
+	copyError := cmd.waitCopying()
+	if err != nil {
+		return err
+	}
+
+	if service.Result != "success" {
+		return &ExitError{
+			Unit:   cmd.Unit,
+			Result: service.Result,
+			Code:   service.ExecMainCode,
+			Status: service.ExecMainStatus,
+		}
+	}
+
+	return copyError
+}
+
+// Run starts the command and waits for it to complete.
+func (cmd *Command) Run(ctx context.Context) error {
+	if err := cmd.Start(ctx); err != nil {
+		return err
+	}
+	return cmd.Wait(ctx)
+}
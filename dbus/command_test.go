@@ -0,0 +1,198 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestExitError(t *testing.T) {
+	tests := []struct {
+		err  ExitError
+		msg  string
+		code int
+	}{
+		{ExitError{Unit: "a.service", Result: "exit-code", Code: cldExited, Status: 3}, "unit a.service: exit status 3", 3},
+		{ExitError{Unit: "a.service", Result: "signal", Code: cldKilled, Status: 9}, "unit a.service: signal: killed", -1},
+		{ExitError{Unit: "a.service", Result: "core-dump", Code: cldDumped, Status: 11}, "unit a.service: signal: segmentation fault", -1},
+		{ExitError{Unit: "a.service", Result: "timeout"}, "unit a.service: timeout", -1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("Error() = %q, want %q", got, tt.msg)
+		}
+		if got := tt.err.ExitCode(); got != tt.code {
+			t.Errorf("%s: ExitCode() = %d, want %d", tt.msg, got, tt.code)
+		}
+	}
+}
+
+func TestCommandProperties(t *testing.T) {
+	c := &Conn{}
+	cmd := c.Command("/bin/sh", "-c", "exit 0")
+	cmd.Env = []string{"FOO=bar"}
+	cmd.Dir = "/tmp"
+	cmd.Stdin = strings.NewReader("input")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.Properties = []Property{PropRemainAfterExit(false)}
+
+	props, files, err := cmd.properties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.closeDescriptors(cmd.closeAfterStart)
+	defer cmd.closeDescriptors(cmd.closeAfterWait)
+	defer cmd.closeDescriptors(cmd.closeAfterCopy)
+
+	// Stdout and Stderr share a single pipe.
+	if len(files) != 3 || files[1] != files[2] {
+		t.Fatalf("unexpected files %v", files)
+	}
+	if len(cmd.goroutines) != 2 {
+		t.Fatalf("got %d copying goroutines, want 2", len(cmd.goroutines))
+	}
+
+	want := map[string]string{
+		"Description":                  "s",
+		"ExecStart":                    "a(sasb)",
+		"AddRef":                       "b",
+		"CollectMode":                  "s",
+		"Environment":                  "as",
+		"WorkingDirectory":             "s",
+		"StandardInputFileDescriptor":  "h",
+		"StandardOutputFileDescriptor": "h",
+		"StandardErrorFileDescriptor":  "h",
+		"RemainAfterExit":              "b",
+	}
+	if len(props) != len(want) {
+		t.Fatalf("got %d properties, want %d", len(props), len(want))
+	}
+	for _, p := range props {
+		sig, ok := want[p.Name]
+		if !ok {
+			t.Errorf("unexpected property %s", p.Name)
+			continue
+		}
+		if got := p.Value.Signature().String(); got != sig {
+			t.Errorf("%s has signature %s, want %s", p.Name, got, sig)
+		}
+	}
+
+	if got := props[0].Value.Value(); got != "/bin/sh -c exit 0" {
+		t.Errorf("Description = %q", got)
+	}
+	if got := props[6].Value.Value(); got != dbus.UnixFD(files[0].Fd()) {
+		t.Errorf("StandardInputFileDescriptor = %v, want %d", got, files[0].Fd())
+	}
+}
+
+// TestCommandCopyOutput checks that output still buffered in the pipes when
+// the service exits is copied completely.
+func TestCommandCopyOutput(t *testing.T) {
+	c := &Conn{}
+	cmd := c.Command("/bin/true")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	_, files, err := cmd.properties()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Play the service: write more than fits into the pipe, then exit.
+	want := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	done := make(chan error, 1)
+	go func() {
+		_, err := files[0].Write(want)
+		files[0].Close()
+		done <- err
+	}()
+
+	cmd.startCopying()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	// What Wait does once the service is gone.
+	cmd.closeDescriptors(cmd.closeAfterWait)
+	if err := cmd.waitCopying(); err != nil {
+		t.Fatalf("copying: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("copied %d bytes, want %d", out.Len(), len(want))
+	}
+}
+
+func TestCommandLookPath(t *testing.T) {
+	c := &Conn{}
+	cmd := c.Command("sh", "-c", "true")
+	if cmd.Path == "sh" || cmd.Args[0] != "sh" {
+		t.Fatalf("Path = %q, Args[0] = %q", cmd.Path, cmd.Args[0])
+	}
+
+	cmd = c.Command("does-not-exist-go-systemd")
+	if err := cmd.Start(t.Context()); err == nil {
+		t.Fatal("expected lookup error")
+	}
+
+	cmd = &Command{Path: "/bin/true", Args: []string{"true"}}
+	if err := cmd.Start(t.Context()); err == nil {
+		t.Fatal("expected error for a Command without Conn")
+	}
+}
+
+func TestCommandRun(t *testing.T) {
+	conn := setupConn(t)
+
+	cmd := conn.Command("/bin/sh", "-c", `read line; echo "out $line $FOO"; echo err >&2; pwd`)
+	cmd.Env = []string{"FOO=bar"}
+	cmd.Dir = "/tmp"
+	cmd.Stdin = strings.NewReader("in\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stdout.String(), "out in bar\n/tmp\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "err\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+
+	cmd = conn.Command("/bin/sh", "-c", "exit 7")
+	cmd.Stdout = os.Stdout
+	err := cmd.Run(t.Context())
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Run() = %v, want *ExitError", err)
+	}
+	if exitErr.ExitCode() != 7 || exitErr.Result != "exit-code" {
+		t.Fatalf("unexpected exit error %+v", exitErr)
+	}
+	if err := cmd.Wait(t.Context()); err == nil {
+		t.Fatal("second Wait should fail")
+	}
+}
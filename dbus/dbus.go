@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -94,8 +95,35 @@ type Conn struct {
 	sigconn *dbus.Conn
 	sigobj  dbus.BusObject
 
+	// connLock protects sysconn and sigconn, which are replaced when
+	// reconnecting. sysobj and sigobj always use the current connections.
+	connLock sync.RWMutex
+	dialBus  func() (*dbus.Conn, error)
+
+	// interactive allows interactive authorization for all calls.
+	interactive atomic.Bool
+
+	// user is set for connections to a user manager, made with
+	// NewUserConnectionContext.
+	user bool
+
+	matches struct {
+		rules      map[string]int
+		subscribed bool
+		sync.Mutex
+	}
+	reconnect struct {
+		events  chan ConnectionEvent
+		done    chan struct{}
+		closed  bool
+		hooks   map[int]func(context.Context)
+		nextKey int
+		sync.Mutex
+	}
+
 	jobListener struct {
-		jobs map[dbus.ObjectPath][]chan<- string
+		jobs    map[dbus.ObjectPath][]chan<- string
+		handles map[dbus.ObjectPath]*connJob
 		sync.Mutex
 	}
 	subStateSubscriber struct {
@@ -110,6 +138,11 @@ type Conn struct {
 		errCh    chan<- error
 		sync.Mutex
 	}
+	signalListeners struct {
+		listeners map[int]func(*dbus.Signal)
+		next      int
+		sync.Mutex
+	}
 }
 
 // Deprecated: use NewWithContext instead.
@@ -149,9 +182,14 @@ func NewUserConnection() (*Conn, error) {
 // authenticates. This can be used to connect to systemd user instances.
 // Callers should call Close() when done with the connection.
 func NewUserConnectionContext(ctx context.Context) (*Conn, error) {
-	return NewConnection(func() (*dbus.Conn, error) {
+	c, err := NewConnection(func() (*dbus.Conn, error) {
 		return dbusAuthHelloConnection(ctx, dbus.SessionBusPrivate)
 	})
+	if err != nil {
+		return nil, err
+	}
+	c.user = true
+	return c, nil
 }
 
 // Deprecated: use NewSystemdConnectionContext instead.
@@ -173,13 +211,40 @@ func NewSystemdConnectionContext(ctx context.Context) (*Conn, error) {
 
 // Close closes an established connection.
 func (c *Conn) Close() {
-	c.sysconn.Close()
-	c.sigconn.Close()
+	c.reconnect.Lock()
+	if !c.reconnect.closed {
+		c.reconnect.closed = true
+		close(c.reconnect.done)
+	}
+	c.reconnect.Unlock()
+
+	c.sys().Close()
+	c.sig().Close()
 }
 
 // Connected returns whether conn is connected
 func (c *Conn) Connected() bool {
-	return c.sysconn.Connected() && c.sigconn.Connected()
+	return c.sys().Connected() && c.sig().Connected()
+}
+
+// sys returns the current connection used to call dbus methods.
+func (c *Conn) sys() *dbus.Conn {
+	c.connLock.RLock()
+	defer c.connLock.RUnlock()
+	return c.sysconn
+}
+
+// sig returns the current connection used to receive dbus signals.
+func (c *Conn) sig() *dbus.Conn {
+	c.connLock.RLock()
+	defer c.connLock.RUnlock()
+	return c.sigconn
+}
+
+// object returns the systemd object at path on the current connection used
+// to call dbus methods.
+func (c *Conn) object(path dbus.ObjectPath) dbus.BusObject {
+	return c.newObject(c.sys, path)
 }
 
 // NewConnection establishes a connection to a bus using a caller-supplied function.
@@ -201,16 +266,22 @@ func NewConnection(dialBus func() (*dbus.Conn, error)) (*Conn, error) {
 
 	c := &Conn{
 		sysconn: sysconn,
-		sysobj:  systemdObject(sysconn),
 		sigconn: sigconn,
-		sigobj:  systemdObject(sigconn),
+		dialBus: dialBus,
 	}
+	c.sysobj = c.newObject(c.sys, "/org/freedesktop/systemd1")
+	c.sigobj = c.newObject(c.sig, "/org/freedesktop/systemd1")
 
 	c.subStateSubscriber.ignore = make(map[dbus.ObjectPath]int64)
 	c.jobListener.jobs = make(map[dbus.ObjectPath][]chan<- string)
+	c.jobListener.handles = make(map[dbus.ObjectPath]*connJob)
+	c.signalListeners.listeners = make(map[int]func(*dbus.Signal))
+	c.matches.rules = make(map[string]int)
+	c.reconnect.done = make(chan struct{})
+	c.reconnect.hooks = make(map[int]func(context.Context))
 
 	// Setup the listeners on jobs so that we can get completions
-	c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
+	c.addMatch(context.Background(),
 		"type='signal', interface='org.freedesktop.systemd1.Manager', member='JobRemoved'")
 
 	c.dispatch()
@@ -261,7 +332,3 @@ func dbusAuthHelloConnection(ctx context.Context, createBus func(opts ...dbus.Co
 
 	return conn, nil
 }
-
-func systemdObject(conn *dbus.Conn) dbus.BusObject {
-	return conn.Object("org.freedesktop.systemd1", dbus.ObjectPath("/org/freedesktop/systemd1"))
-}
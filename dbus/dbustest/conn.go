@@ -0,0 +1,164 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// serverGUID is the server address GUID sent to clients when authenticating.
+const serverGUID = "0123456789abcdef0123456789abcdef"
+
+// serverAuth performs the server side of the authentication handshake on
+// rw. Every mechanism offered by the client is accepted. Unix file
+// descriptors are not supported.
+//
+// It reads byte by byte, as the client may send its first message directly
+// after BEGIN.
+func serverAuth(rw io.ReadWriter) error {
+	var b [1]byte
+	if _, err := io.ReadFull(rw, b[:]); err != nil {
+		return err
+	}
+	if b[0] != 0 {
+		return errors.New("dbustest: authentication protocol error")
+	}
+
+	for {
+		var line []byte
+		for !bytes.HasSuffix(line, []byte("\r\n")) {
+			if _, err := io.ReadFull(rw, b[:]); err != nil {
+				return err
+			}
+			line = append(line, b[0])
+		}
+
+		var reply string
+		fields := strings.Fields(string(line))
+		switch {
+		case len(fields) == 0:
+			reply = "ERROR"
+		case fields[0] == "AUTH" && len(fields) == 1:
+			reply = "REJECTED EXTERNAL ANONYMOUS"
+		case fields[0] == "AUTH":
+			reply = "OK " + serverGUID
+		case fields[0] == "BEGIN":
+			return nil
+		default:
+			reply = "ERROR"
+		}
+		if _, err := io.WriteString(rw, reply+"\r\n"); err != nil {
+			return err
+		}
+	}
+}
+
+// serverTransport lets a godbus connection act as the server side of a
+// peer-to-peer connection. godbus can only authenticate as a client, so the
+// handshake is answered locally, without sending anything: writes are
+// discarded until BEGIN, and reads return canned replies.
+type serverTransport struct {
+	net.Conn
+	replies []byte
+	begun   bool
+}
+
+func newServerTransport(conn net.Conn) *serverTransport {
+	return &serverTransport{
+		Conn:    conn,
+		replies: []byte("REJECTED ANONYMOUS\r\nOK " + serverGUID + "\r\n"),
+	}
+}
+
+func (t *serverTransport) Read(p []byte) (int, error) {
+	if len(t.replies) > 0 {
+		n := copy(p, t.replies)
+		t.replies = t.replies[n:]
+		return n, nil
+	}
+	return t.Conn.Read(p)
+}
+
+func (t *serverTransport) Write(p []byte) (int, error) {
+	if !t.begun {
+		t.begun = bytes.Equal(p, []byte("BEGIN\r\n"))
+		return len(p), nil
+	}
+	return t.Conn.Write(p)
+}
+
+// dialPipe returns an authenticated client connection, and the server side
+// of it, set up by serve before any message is read.
+func dialPipe(serve func(*dbus.Conn) error) (*dbus.Conn, *dbus.Conn, error) {
+	client, server := net.Pipe()
+
+	type result struct {
+		conn *dbus.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := accept(server, serve)
+		done <- result{conn, err}
+	}()
+
+	conn, err := dbus.NewConn(client)
+	if err == nil {
+		err = conn.Auth([]dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))})
+	}
+	if err != nil {
+		client.Close()
+		server.Close()
+		<-done
+		return nil, nil, err
+	}
+
+	res := <-done
+	if res.err != nil {
+		conn.Close()
+		return nil, nil, res.err
+	}
+	return conn, res.conn, nil
+}
+
+func accept(server net.Conn, serve func(*dbus.Conn) error) (*dbus.Conn, error) {
+	if err := serverAuth(server); err != nil {
+		server.Close()
+		return nil, err
+	}
+
+	conn, err := dbus.NewConn(newServerTransport(server))
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	if err := serve(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Auth([]dbus.Auth{dbus.AuthAnonymous()}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
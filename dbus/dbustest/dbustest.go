@@ -0,0 +1,525 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbustest provides an in-memory fake of the systemd D-Bus API, for
+// testing code built on the dbus package without systemd or root.
+//
+// A [Systemd] implements the org.freedesktop.systemd1.Manager interface and
+// the unit and job objects on peer-to-peer connections, like the private
+// socket of systemd. Jobs are run by a simulated job engine, which moves
+// units through their states and emits the signals systemd would:
+//
+//	sd := dbustest.NewSystemd()
+//	defer sd.Close()
+//	sd.AddUnit(dbustest.Unit{Name: "foo.service"})
+//
+//	conn, err := dbus.NewConnection(sd.Dial)
+//	...
+//	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+//
+// Unix file descriptors cannot be passed, and unit files are not read.
+package dbustest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	managerPath = dbus.ObjectPath("/org/freedesktop/systemd1")
+	unitRoot    = dbus.ObjectPath("/org/freedesktop/systemd1/unit")
+	jobRoot     = dbus.ObjectPath("/org/freedesktop/systemd1/job")
+	unitPrefix  = string(unitRoot) + "/"
+	jobPrefix   = string(jobRoot) + "/"
+
+	unitInterface = "org.freedesktop.systemd1.Unit"
+)
+
+// Unit describes a unit added to the fake with [Systemd.AddUnit].
+type Unit struct {
+	Name        string
+	Description string
+
+	// LoadState is the load state of the unit, "loaded" by default. Jobs
+	// for units that are "not-found" fail with NoSuchUnit, units that are
+	// "masked" cannot be started.
+	LoadState string
+
+	// ActiveState and SubState are the initial state of the unit,
+	// "inactive" and "dead" by default.
+	ActiveState string
+	SubState    string
+
+	// UnitFileState is the enablement state of the unit file, e.g. enabled
+	// or disabled. Units with an empty UnitFileState have no unit file.
+	UnitFileState string
+
+	// StartResult is the result of start jobs, "done" by default. With any
+	// other result, e.g. "failed", starting the unit fails and the unit
+	// enters the failed state.
+	StartResult string
+
+	// Properties holds additional properties of the unit, keyed by the short
+	// interface name, e.g. "Unit" or "Service", and the property name.
+	Properties map[string]map[string]any
+}
+
+// Systemd is a fake systemd manager. It is safe for concurrent use.
+type Systemd struct {
+	mu       sync.Mutex
+	conns    map[*dbus.Conn]struct{}
+	units    map[string]*unit
+	paths    map[dbus.ObjectPath]*unit
+	jobs     map[uint32]*job
+	nextJob  uint32
+	jobDelay time.Duration
+	closed   bool
+}
+
+type unit struct {
+	name        string
+	path        dbus.ObjectPath
+	startResult string
+	transient   bool
+	refs        int
+	job         *job
+
+	// props holds the properties by full interface name.
+	props map[string]map[string]dbus.Variant
+}
+
+// NewSystemd returns a fake systemd manager without any units.
+func NewSystemd() *Systemd {
+	return &Systemd{
+		conns:   make(map[*dbus.Conn]struct{}),
+		units:   make(map[string]*unit),
+		paths:   make(map[dbus.ObjectPath]*unit),
+		jobs:    make(map[uint32]*job),
+		nextJob: 1,
+	}
+}
+
+// Dial opens a new connection to the fake. It can be passed to
+// [sd.NewConnection].
+func (s *Systemd) Dial() (*dbus.Conn, error) {
+	client, server, err := dialPipe(s.serve)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		client.Close()
+		server.Close()
+		return nil, fmt.Errorf("dbustest: closed")
+	}
+	s.conns[server] = struct{}{}
+	go func() {
+		<-server.Context().Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.conns, server)
+	}()
+
+	return client, nil
+}
+
+// Conn returns a new [sd.Conn] connected to the fake.
+func (s *Systemd) Conn() (*sd.Conn, error) {
+	return sd.NewConnection(s.Dial)
+}
+
+// serve exports the systemd objects on conn.
+func (s *Systemd) serve(conn *dbus.Conn) error {
+	exports := []struct {
+		v       any
+		path    dbus.ObjectPath
+		iface   string
+		subtree bool
+	}{
+		{&bus{}, "/org/freedesktop/DBus", "org.freedesktop.DBus", false},
+		{&manager{s}, managerPath, "org.freedesktop.systemd1.Manager", false},
+		{&properties{s}, managerPath, "org.freedesktop.DBus.Properties", false},
+		{&unitObject{s}, unitRoot, unitInterface, true},
+		{&properties{s}, unitRoot, "org.freedesktop.DBus.Properties", true},
+		{&jobObject{s}, jobRoot, "org.freedesktop.systemd1.Job", true},
+		{&properties{s}, jobRoot, "org.freedesktop.DBus.Properties", true},
+	}
+	for _, e := range exports {
+		var err error
+		if e.subtree {
+			err = conn.ExportSubtree(e.v, e.path, e.iface)
+		} else {
+			err = conn.Export(e.v, e.path, e.iface)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all connections. Pending jobs are not run anymore.
+func (s *Systemd) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.Disconnect()
+}
+
+// Disconnect closes all connections, like a restart of the bus or a reexec
+// of systemd would. Units and jobs are kept, and new connections can be made.
+func (s *Systemd) Disconnect() {
+	s.mu.Lock()
+	conns := slices.Collect(maps.Keys(s.conns))
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// SetJobDelay sets how long jobs wait before they run. By default, jobs run
+// right after they were enqueued. A delay gives the client time to observe
+// or cancel the job.
+func (s *Systemd) SetJobDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobDelay = d
+}
+
+// AddUnit adds u to the fake and emits UnitNew. If a unit with the same name
+// exists, it is updated instead.
+func (s *Systemd) AddUnit(u Unit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.loadUnit(u.Name, "loaded")
+	st.startResult = u.StartResult
+	st.set(unitInterface, map[string]any{
+		"Description":   orDefault(u.Description, u.Name),
+		"LoadState":     orDefault(u.LoadState, "loaded"),
+		"ActiveState":   orDefault(u.ActiveState, "inactive"),
+		"SubState":      orDefault(u.SubState, "dead"),
+		"UnitFileState": u.UnitFileState,
+	})
+	for iface, props := range u.Properties {
+		st.set(interfaceName(iface), props)
+	}
+}
+
+// SetUnitState changes the state of the unit name, as if it changed on its
+// own, e.g. because its main process exited, and emits PropertiesChanged.
+func (s *Systemd) SetUnitState(name, activeState, subState string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.units[name]
+	if !ok {
+		return fmt.Errorf("dbustest: no unit %s", name)
+	}
+	s.setState(u, activeState, subState)
+	s.collect(u)
+	return nil
+}
+
+// Exit simulates the exit of the main process of the service name with the
+// given exit status. The service becomes inactive if status is 0, and failed
+// otherwise.
+func (s *Systemd) Exit(name string, status int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.units[name]
+	if !ok {
+		return fmt.Errorf("dbustest: no unit %s", name)
+	}
+
+	result, active, sub := "success", "inactive", "dead"
+	if status != 0 {
+		result, active, sub = "exit-code", "failed", "failed"
+	}
+	changed := map[string]any{
+		"Result":         result,
+		"ExecMainCode":   int32(1), // CLD_EXITED
+		"ExecMainStatus": int32(status),
+		"MainPID":        uint32(0),
+	}
+	u.set("org.freedesktop.systemd1.Service", changed)
+	s.emitPropertiesChanged(u, "org.freedesktop.systemd1.Service", changed)
+	s.setState(u, active, sub)
+	s.collect(u)
+	return nil
+}
+
+// SetProperties changes properties of the unit name, e.g. its resource usage
+// counters, with the short interface name, e.g. "Unit" or "Service", and
+// emits PropertiesChanged.
+func (s *Systemd) SetProperties(name, iface string, props map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.units[name]
+	if !ok {
+		return fmt.Errorf("dbustest: no unit %s", name)
+	}
+	u.set(interfaceName(iface), props)
+	s.emitPropertiesChanged(u, interfaceName(iface), props)
+	return nil
+}
+
+// Property returns the value of a property of the unit name, with the short
+// interface name, e.g. "Unit" or "Service".
+func (s *Systemd) Property(name, iface, property string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.units[name]
+	if !ok {
+		return nil, false
+	}
+	v, ok := u.props[interfaceName(iface)][property]
+	if !ok {
+		return nil, false
+	}
+	return v.Value(), true
+}
+
+// Units returns the names of all units known to the fake.
+func (s *Systemd) Units() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(s.units))
+}
+
+// loadUnit returns the unit name, adding it with loadState if it does not
+// exist. The caller must hold s.mu.
+func (s *Systemd) loadUnit(name, loadState string) *unit {
+	if u, ok := s.units[name]; ok {
+		return u
+	}
+
+	u := &unit{
+		name:  name,
+		path:  dbus.ObjectPath(unitPrefix + sd.PathBusEscape(name)),
+		props: make(map[string]map[string]dbus.Variant),
+	}
+	u.set(unitInterface, map[string]any{
+		"Id":                     name,
+		"Names":                  []string{name},
+		"Following":              "",
+		"Description":            name,
+		"LoadState":              loadState,
+		"ActiveState":            "inactive",
+		"SubState":               "dead",
+		"UnitFileState":          "",
+		"Transient":              false,
+		"Job":                    jobRef{0, "/"},
+		"CollectMode":            "inactive",
+		"InvocationID":           []byte{},
+		"ActiveEnterTimestamp":   uint64(0),
+		"ActiveExitTimestamp":    uint64(0),
+		"InactiveEnterTimestamp": uint64(0),
+		"InactiveExitTimestamp":  uint64(0),
+		"StateChangeTimestamp":   uint64(0),
+	})
+	if unitType(name) == "service" {
+		u.set(typeInterface(name), map[string]any{
+			"Type":           "simple",
+			"Result":         "success",
+			"MainPID":        uint32(0),
+			"ExecMainCode":   int32(0),
+			"ExecMainStatus": int32(0),
+			"NRestarts":      uint32(0),
+		})
+	}
+
+	s.units[name] = u
+	s.paths[u.path] = u
+	s.emit(managerPath, "org.freedesktop.systemd1.Manager.UnitNew", name, u.path)
+	return u
+}
+
+// setState changes the state of u and emits PropertiesChanged. The caller
+// must hold s.mu.
+func (s *Systemd) setState(u *unit, activeState, subState string) {
+	old := u.activeState()
+	if old == activeState && u.props[unitInterface]["SubState"].Value() == subState {
+		return
+	}
+
+	now := uint64(time.Now().UnixMicro())
+	changed := map[string]any{
+		"ActiveState":          activeState,
+		"SubState":             subState,
+		"StateChangeTimestamp": now,
+	}
+	switch {
+	case activeState == "active" && old != "active":
+		changed["ActiveEnterTimestamp"] = now
+	case activeState != "active" && old == "active":
+		changed["ActiveExitTimestamp"] = now
+	}
+	switch {
+	case isInactive(activeState) && !isInactive(old):
+		changed["InactiveEnterTimestamp"] = now
+	case !isInactive(activeState) && isInactive(old):
+		changed["InactiveExitTimestamp"] = now
+	}
+
+	u.set(unitInterface, changed)
+	s.emitPropertiesChanged(u, unitInterface, changed)
+}
+
+// collect removes transient units that are no longer referenced or active,
+// following their CollectMode. The caller must hold s.mu.
+func (s *Systemd) collect(u *unit) {
+	if !u.transient || u.refs > 0 || u.job != nil {
+		return
+	}
+	switch u.activeState() {
+	case "inactive":
+	case "failed":
+		if u.props[unitInterface]["CollectMode"].Value() != "inactive-or-failed" {
+			return
+		}
+	default:
+		return
+	}
+
+	delete(s.units, u.name)
+	delete(s.paths, u.path)
+	s.emit(managerPath, "org.freedesktop.systemd1.Manager.UnitRemoved", u.name, u.path)
+}
+
+// emit sends a signal to all connections. The caller must hold s.mu, which
+// keeps the signals in order.
+func (s *Systemd) emit(path dbus.ObjectPath, name string, values ...any) {
+	for conn := range s.conns {
+		_ = conn.Emit(path, name, values...)
+	}
+}
+
+func (s *Systemd) emitPropertiesChanged(u *unit, iface string, changed map[string]any) {
+	variants := make(map[string]dbus.Variant, len(changed))
+	for k, v := range changed {
+		variant, ok := v.(dbus.Variant)
+		if !ok {
+			variant = dbus.MakeVariant(v)
+		}
+		variants[k] = variant
+	}
+	s.emit(u.path, "org.freedesktop.DBus.Properties.PropertiesChanged", iface, variants, []string{})
+}
+
+func (u *unit) set(iface string, props map[string]any) {
+	if u.props[iface] == nil {
+		u.props[iface] = make(map[string]dbus.Variant)
+	}
+	for k, v := range props {
+		if variant, ok := v.(dbus.Variant); ok {
+			u.props[iface][k] = variant
+		} else {
+			u.props[iface][k] = dbus.MakeVariant(v)
+		}
+	}
+}
+
+func (u *unit) activeState() string {
+	s, _ := u.props[unitInterface]["ActiveState"].Value().(string)
+	return s
+}
+
+func (u *unit) loadState() string {
+	s, _ := u.props[unitInterface]["LoadState"].Value().(string)
+	return s
+}
+
+func (u *unit) status() sd.UnitStatus {
+	st := sd.UnitStatus{
+		Name:    u.name,
+		Path:    u.path,
+		JobPath: "/",
+	}
+	props := u.props[unitInterface]
+	st.Description, _ = props["Description"].Value().(string)
+	st.LoadState, _ = props["LoadState"].Value().(string)
+	st.ActiveState, _ = props["ActiveState"].Value().(string)
+	st.SubState, _ = props["SubState"].Value().(string)
+	st.Followed, _ = props["Following"].Value().(string)
+	if u.job != nil {
+		st.JobId, st.JobType, st.JobPath = u.job.id, u.job.jobType, u.job.path
+	}
+	return st
+}
+
+func isInactive(activeState string) bool {
+	return activeState == "inactive" || activeState == "failed"
+}
+
+// unitType returns the type suffix of the unit name, e.g. service.
+func unitType(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// typeInterface returns the interface specific to the type of the unit
+// name, e.g. org.freedesktop.systemd1.Service.
+func typeInterface(name string) string {
+	t := unitType(name)
+	return interfaceName(strings.ToUpper(t[:1]) + t[1:])
+}
+
+// activeSubState returns the sub state of an active unit of type t.
+func activeSubState(t string) string {
+	switch t {
+	case "service", "scope":
+		return "running"
+	case "socket":
+		return "listening"
+	case "mount":
+		return "mounted"
+	case "device":
+		return "plugged"
+	case "timer", "path", "automount":
+		return "waiting"
+	default:
+		return "active"
+	}
+}
+
+// interfaceName returns the full name of the systemd1 interface iface,
+// which may be given by its short name.
+func interfaceName(iface string) string {
+	if strings.Contains(iface, ".") {
+		return iface
+	}
+	return "org.freedesktop.systemd1." + iface
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,753 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/dbus/dbustest"
+	"github.com/coreos/go-systemd/v22/unit"
+	"github.com/godbus/dbus/v5"
+)
+
+func setup(t *testing.T, units ...dbustest.Unit) (*dbustest.Systemd, *sd.Conn) {
+	t.Helper()
+
+	fake := dbustest.NewSystemd()
+	t.Cleanup(fake.Close)
+	for _, u := range units {
+		fake.AddUnit(u)
+	}
+
+	conn, err := fake.Conn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+	return fake, conn
+}
+
+func TestListUnits(t *testing.T) {
+	_, conn := setup(t,
+		dbustest.Unit{Name: "foo.service", Description: "Foo", ActiveState: "active", SubState: "running"},
+		dbustest.Unit{Name: "bar.socket"},
+	)
+	ctx := context.Background()
+
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 2 {
+		t.Fatalf("got %d units, want 2", len(units))
+	}
+	if u := units[1]; u.Name != "foo.service" || u.Description != "Foo" || u.ActiveState != "active" || u.SubState != "running" {
+		t.Errorf("unexpected unit %+v", u)
+	}
+
+	units, err = conn.ListUnitsByPatternsContext(ctx, []string{"inactive"}, []string{"*.socket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(units) != 1 || units[0].Name != "bar.socket" {
+		t.Errorf("ListUnitsByPatterns returned %v, want bar.socket", units)
+	}
+}
+
+func TestStartStopUnit(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx := context.Background()
+
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := fake.Property("foo.service", "Unit", "ActiveState"); state != "active" {
+		t.Errorf("ActiveState = %v after starting, want active", state)
+	}
+
+	props, err := conn.GetTypedUnitProperties(ctx, "foo.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.ActiveState != "active" || props.SubState != "running" || props.ActiveEnterTimestamp.IsZero() {
+		t.Errorf("unexpected properties %s/%s, entered %v", props.ActiveState, props.SubState, props.ActiveEnterTimestamp)
+	}
+
+	ch := make(chan string, 1)
+	if _, err := conn.StopUnitContext(ctx, "foo.service", "replace", ch); err != nil {
+		t.Fatal(err)
+	}
+	if result := <-ch; result != "done" {
+		t.Errorf("stop job result %q, want done", result)
+	}
+	if state, _ := fake.Property("foo.service", "Unit", "ActiveState"); state != "inactive" {
+		t.Errorf("ActiveState = %v after stopping, want inactive", state)
+	}
+}
+
+func TestStartUnitErrors(t *testing.T) {
+	_, conn := setup(t,
+		dbustest.Unit{Name: "failing.service", StartResult: "failed"},
+		dbustest.Unit{Name: "masked.service", LoadState: "masked"},
+	)
+	ctx := context.Background()
+
+	job, err := conn.StartUnitJob(ctx, "failing.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); !errors.Is(err, sd.JobFailed) {
+		t.Errorf("Wait() = %v, want %v", err, sd.JobFailed)
+	}
+	state, err := sd.GetProperty[string](ctx, conn, "failing.service", "Unit", "ActiveState")
+	if err != nil || state != "failed" {
+		t.Errorf("ActiveState = %q (%v), want failed", state, err)
+	}
+
+	for unit, want := range map[string]struct {
+		name     string
+		sentinel error
+	}{
+		"masked.service":  {"org.freedesktop.systemd1.UnitMasked", sd.ErrUnitMasked},
+		"missing.service": {"org.freedesktop.systemd1.NoSuchUnit", sd.ErrNoSuchUnit},
+	} {
+		var dbusErr dbus.Error
+		var svcErr *sd.ServiceError
+		_, err := conn.StartUnitJob(ctx, unit, "replace")
+		if !errors.As(err, &dbusErr) || dbusErr.Name != want.name {
+			t.Errorf("starting %s: got %v, want %s", unit, err, want.name)
+		}
+		if !errors.Is(err, want.sentinel) || !errors.As(err, &svcErr) || svcErr.Name != want.name {
+			t.Errorf("starting %s: got %v, want %v", unit, err, want.sentinel)
+		}
+	}
+
+	var dbusErr dbus.Error
+	_, err = conn.StartUnitJob(ctx, "failing.service", "bogus")
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.freedesktop.DBus.Error.InvalidArgs" {
+		t.Errorf("invalid job mode: got %v", err)
+	}
+}
+
+// TestUnitNames checks that the existing methods still send every name
+// systemd accepts on D-Bus, including templates, instances of any type and
+// escaped names, instead of rejecting them locally.
+func TestUnitNames(t *testing.T) {
+	names := []string{
+		"getty@.service",
+		"getty@tty1.service",
+		"foo@bar@baz.service",
+		"user@1000.mount",
+		`foo\x2dbar:baz_1.socket`,
+		`dev-disk-by\x2dlabel-root.device`,
+		"home--user.mount",
+		"-.slice",
+		strings.Repeat("a", unit.NameMax-len(".service")) + ".service",
+	}
+	var units []dbustest.Unit
+	for _, name := range names {
+		units = append(units, dbustest.Unit{Name: name})
+	}
+	_, conn := setup(t, units...)
+	ctx := context.Background()
+
+	for _, name := range names {
+		job, err := conn.StartUnitJob(ctx, name, "replace")
+		if err != nil {
+			t.Errorf("StartUnitJob(%q): %v", name, err)
+		} else if err := job.Wait(ctx); err != nil {
+			t.Errorf("starting %s: %v", name, err)
+		}
+		if _, err := conn.GetUnitPropertiesContext(ctx, name); err != nil {
+			t.Errorf("GetUnitPropertiesContext(%q): %v", name, err)
+		}
+		if err := conn.SetUnitPropertiesContext(ctx, name, true, sd.PropDescription("renamed")); err != nil {
+			t.Errorf("SetUnitPropertiesContext(%q): %v", name, err)
+		}
+		if err := conn.KillUnitWithTarget(ctx, name, sd.All, int32(syscall.SIGHUP)); err != nil {
+			t.Errorf("KillUnitWithTarget(%q): %v", name, err)
+		}
+		if err := conn.ResetFailedUnitContext(ctx, name); err != nil {
+			t.Errorf("ResetFailedUnitContext(%q): %v", name, err)
+		}
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	fake.SetJobDelay(time.Hour)
+	ctx := context.Background()
+
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := conn.ListJobsContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Id != job.ID() || jobs[0].JobType != "start" || jobs[0].Status != "waiting" {
+		t.Errorf("unexpected jobs %+v", jobs)
+	}
+
+	// Listing a job returns the handle it was enqueued with.
+	for range 2 {
+		handles, err := conn.GetJobs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(handles) != 1 || handles[0] != job {
+			t.Errorf("GetJobs() = %v, want the handle of job %d", handles, job.ID())
+		}
+	}
+
+	// A conflicting job is refused in fail mode.
+	var dbusErr dbus.Error
+	_, err = conn.StopUnitJob(ctx, "foo.service", "fail")
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.freedesktop.systemd1.TransactionIsDestructive" || !errors.Is(err, sd.ErrTransactionIsDestructive) {
+		t.Errorf("conflicting job: got %v", err)
+	}
+
+	if err := job.Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); !errors.Is(err, sd.JobCanceled) {
+		t.Errorf("Wait() = %v, want %v", err, sd.JobCanceled)
+	}
+}
+
+func TestTransientUnit(t *testing.T) {
+	fake, conn := setup(t)
+	ctx := context.Background()
+
+	props := []sd.Property{
+		sd.PropDescription("transient test"),
+		sd.PropExecStart([]string{"/bin/true"}, false),
+		{Name: "AddRef", Value: dbus.MakeVariant(true)},
+	}
+	job, err := conn.StartTransientUnitJob(ctx, "transient.service", "fail", props, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	unit, err := conn.GetTypedUnitProperties(ctx, "transient.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit.Description != "transient test" || !unit.Transient {
+		t.Errorf("unexpected unit %q, transient %v", unit.Description, unit.Transient)
+	}
+
+	_, err = conn.StartTransientUnitJob(ctx, "transient.service", "fail", props, nil)
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) || dbusErr.Name != "org.freedesktop.systemd1.UnitExists" {
+		t.Errorf("starting again: got %v", err)
+	}
+
+	if err := fake.Exit("transient.service", 3); err != nil {
+		t.Fatal(err)
+	}
+	service, err := conn.GetTypedServiceProperties(ctx, "transient.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.Result != "exit-code" || service.ExecMainStatus != 3 {
+		t.Errorf("unexpected result %s, status %d", service.Result, service.ExecMainStatus)
+	}
+
+	// The unit is kept until it is failed and unreferenced.
+	if err := conn.ResetFailedUnitContext(ctx, "transient.service"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.Units()) != 1 {
+		t.Fatalf("referenced unit was collected")
+	}
+	raw, err := fake.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+	obj := raw.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1/unit/transient_2eservice")
+	if err := obj.Call("org.freedesktop.systemd1.Unit.Unref", 0).Store(); err != nil {
+		t.Fatal(err)
+	}
+	if units := fake.Units(); len(units) != 0 {
+		t.Errorf("unit was not collected: %v", units)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache, err := conn.NewUnitStateCache(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, stop := cache.Changes(10)
+	defer stop()
+
+	events, err := conn.Events(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var removed *sd.JobRemovedEvent
+	for removed == nil {
+		select {
+		case ev := <-events:
+			removed, _ = ev.(*sd.JobRemovedEvent)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for JobRemoved")
+		}
+	}
+	if removed.ID != job.ID() || removed.Unit != "foo.service" || removed.Result != sd.JobDone {
+		t.Errorf("unexpected event %+v", removed)
+	}
+
+	for {
+		select {
+		case change := <-changes:
+			if change.New != nil && change.New.ActiveState == "active" {
+				if err := fake.SetUnitState("foo.service", "failed", "failed"); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			if change.New != nil && change.New.ActiveState == "failed" {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for state change")
+		}
+	}
+}
+
+// TestEventsSlowConsumer checks that an Events receiver that does not receive
+// does not hold up job completion, and still gets all events in order.
+func TestEventsSlowConsumer(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service"}, dbustest.Unit{Name: "bar.service"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := conn.Events(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []uint32
+	for _, name := range []string{"foo.service", "bar.service"} {
+		job, err := conn.StartUnitJob(ctx, name, "replace")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := job.Wait(ctx); err != nil {
+			t.Fatalf("waiting for %s while events are not received: %v", name, err)
+		}
+		ids = append(ids, job.ID())
+	}
+
+	for len(ids) > 0 {
+		select {
+		case ev := <-events:
+			if removed, ok := ev.(*sd.JobRemovedEvent); ok {
+				if removed.ID != ids[0] {
+					t.Fatalf("got JobRemoved for job %d, want %d", removed.ID, ids[0])
+				}
+				ids = ids[1:]
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for JobRemoved")
+		}
+	}
+}
+
+// TestSubscriberContext checks that a subscriber is canceled with the context
+// it was created with.
+func TestSubscriberContext(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s, stop, err := conn.NewSubscriber(ctx, sd.SubscriberOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	cancel()
+	select {
+	case _, ok := <-s.Updates():
+		if ok {
+			t.Fatal("got an update after canceling the context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update channel not closed after canceling the context")
+	}
+}
+
+// TestSubscriberSlowConsumer checks that a subscriber that never receives
+// does not hold up other subscribers or job completion.
+func TestSubscriberSlowConsumer(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The zero options block, with an unbuffered channel.
+	_, stopIdle, err := conn.NewSubscriber(ctx, sd.SubscriberOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stopIdle()
+	s, stop, err := conn.NewSubscriber(ctx, sd.SubscriberOptions{Units: []string{"foo.service"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil {
+		t.Fatalf("waiting for the job while a subscriber does not receive: %v", err)
+	}
+
+	for {
+		select {
+		case u := <-s.Updates():
+			if u.Changed["ActiveState"].Value() == "active" {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the unit to become active")
+		}
+	}
+}
+
+func TestUnitFiles(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service", UnitFileState: "disabled"})
+	ctx := context.Background()
+
+	_, changes, err := conn.EnableUnitFilesContext(ctx, []string{"foo.service"}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Type != "symlink" {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+	state, err := conn.GetUnitFileState(ctx, "foo.service")
+	if err != nil || state != "enabled" {
+		t.Errorf("GetUnitFileState() = %q (%v), want enabled", state, err)
+	}
+
+	files, err := conn.ListUnitFilesContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Type != "enabled" {
+		t.Errorf("unexpected unit files %+v", files)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	fake.SetJobDelay(50 * time.Millisecond)
+	ctx := context.Background()
+
+	events := conn.EnableReconnect(sd.ReconnectOptions{MinBackoff: time.Millisecond})
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Disconnect()
+	for {
+		ev := <-events
+		if ev.State == sd.ConnectionRestored {
+			break
+		}
+	}
+
+	// The job survived, and its completion is seen on the new connection.
+	if err := job.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ListUnitsContext(ctx); err != nil {
+		t.Errorf("calling after reconnecting: %v", err)
+	}
+}
+
+// TestReconnectLostJob checks that a job finishing while disconnected is
+// reported as lost after reconnecting.
+func TestReconnectLostJob(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	fake.SetJobDelay(50 * time.Millisecond)
+	ctx := context.Background()
+
+	events := conn.EnableReconnect(sd.ReconnectOptions{MinBackoff: 500 * time.Millisecond})
+	job, err := conn.StartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Disconnect()
+	for {
+		ev := <-events
+		if ev.State == sd.ConnectionRestored {
+			if len(ev.LostJobs) != 1 || ev.LostJobs[0] != job.Path() {
+				t.Errorf("lost jobs = %v, want [%s]", ev.LostJobs, job.Path())
+			}
+			break
+		}
+	}
+	if err := job.Wait(ctx); !errors.Is(err, sd.JobLost) {
+		t.Errorf("Wait() = %v, want %v", err, sd.JobLost)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	fake, conn := setup(t)
+	ctx := context.Background()
+
+	cmd := conn.Command("/bin/false")
+	if err := cmd.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.Exit(cmd.Unit, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var exitErr *sd.ExitError
+	if err := cmd.Wait(ctx); !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Errorf("Wait() = %v, want exit status 1", err)
+	}
+	if units := fake.Units(); len(units) != 0 {
+		t.Errorf("unit was not collected: %v", units)
+	}
+}
+
+func TestUnitsPropertiesBatch(t *testing.T) {
+	_, conn := setup(t,
+		dbustest.Unit{Name: "foo.service", ActiveState: "active"},
+		dbustest.Unit{Name: "bar.socket"},
+	)
+	ctx := context.Background()
+
+	results := conn.GetUnitsPropertiesBatch(ctx, []string{"foo.service", "bar.socket"}, []string{"Service"}, sd.BatchOptions{
+		Concurrency: 1,
+		Properties:  []string{"Type", "ActiveState"},
+	})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if r := results[0]; r.Name != "foo.service" || r.Err != nil || len(r.Properties) != 1 || r.Properties["Type"] == nil {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r := results[1]; r.Name != "bar.socket" || r.Err == nil {
+		t.Errorf("reading the Service interface of a socket did not fail: %+v", r)
+	}
+
+	// Named properties are looked up on the Unit and the type specific
+	// interface, and those neither has are left out.
+	results = conn.GetUnitsPropertiesBatch(ctx, []string{"foo.service"}, nil, sd.BatchOptions{
+		Properties: []string{"Id", "Type", "Bogus"},
+	})
+	if r := results[0]; r.Err != nil || len(r.Properties) != 2 || r.Properties["Id"] != "foo.service" || r.Properties["Type"] == nil {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	results, err := conn.GetUnitsPropertiesByPatterns(ctx, nil, []string{"*"}, nil, sd.BatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Name, r.Err)
+		} else if r.Properties["Id"] != r.Name {
+			t.Errorf("%s: Unit interface not read: %v", r.Name, r.Properties)
+		}
+	}
+	if r := results[0]; r.Name == "foo.service" && r.Properties["Type"] == nil {
+		t.Errorf("Service interface not read: %v", r.Properties)
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{
+		Name: "foo.service",
+		Properties: map[string]map[string]any{
+			"Service": {
+				"MemoryCurrent":  uint64(1 << 20),
+				"MemoryPeak":     uint64(2 << 20),
+				"CPUUsageNSec":   uint64(0),
+				"TasksCurrent":   uint64(3),
+				"IOReadBytes":    uint64(0),
+				"IOWriteBytes":   uint64(0),
+				"IPIngressBytes": sd.UsageUnknown,
+				"IPEgressBytes":  sd.UsageUnknown,
+			},
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := conn.StartUnitContext(ctx, "foo.service", "replace", nil); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := conn.GetResourceUsage(ctx, "foo.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.MemoryCurrent != 1<<20 || usage.TasksCurrent != 3 || usage.IPIngressBytes != sd.UsageUnknown || len(usage.InvocationID) == 0 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+
+	samples := conn.SampleResourceUsage(ctx, sd.SamplerOptions{
+		Units:    []string{"foo.service", "missing.service"},
+		Interval: 10 * time.Millisecond,
+	})
+	first := <-samples
+	if first[0].Err != nil || first[0].Elapsed != 0 || first[1].Err == nil {
+		t.Fatalf("unexpected first round %+v", first)
+	}
+
+	if err := fake.SetProperties("foo.service", "Service", map[string]any{
+		"CPUUsageNSec": uint64(time.Second),
+		"IOReadBytes":  uint64(4096),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var s sd.ResourceSample
+	for s.CPUTime == 0 {
+		s = (<-samples)[0]
+		if s.Err != nil || s.Reset {
+			t.Fatalf("unexpected sample %+v", s)
+		}
+	}
+	if s.CPUTime != time.Second || s.IOReadBytes != 4096 || s.CPUPercent <= 0 || s.IOReadRate <= 0 {
+		t.Errorf("unexpected sample %+v", s)
+	}
+
+	if _, err := conn.RestartUnitContext(ctx, "foo.service", "replace", nil); err != nil {
+		t.Fatal(err)
+	}
+	for !s.Reset {
+		s = (<-samples)[0]
+	}
+
+	cancel()
+	for range samples {
+	}
+}
+
+// TestResourceUsageMissing checks that counters a unit does not have are
+// unknown rather than zero, and that counters going backwards are reported
+// as a reset.
+func TestResourceUsageMissing(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{
+		Name:        "foo.service",
+		ActiveState: "active",
+		Properties: map[string]map[string]any{
+			"Service": {
+				"CPUUsageNSec": uint64(time.Second),
+			},
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	usage, err := conn.GetResourceUsage(ctx, "foo.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range map[string]uint64{
+		"MemoryCurrent":  usage.MemoryCurrent,
+		"MemoryPeak":     usage.MemoryPeak,
+		"TasksCurrent":   usage.TasksCurrent,
+		"IOReadBytes":    usage.IOReadBytes,
+		"IOWriteBytes":   usage.IOWriteBytes,
+		"IPIngressBytes": usage.IPIngressBytes,
+		"IPEgressBytes":  usage.IPEgressBytes,
+	} {
+		if v != sd.UsageUnknown {
+			t.Errorf("missing %s = %d, want UsageUnknown", name, v)
+		}
+	}
+
+	samples := conn.SampleResourceUsage(ctx, sd.SamplerOptions{
+		Units:    []string{"foo.service"},
+		Interval: 10 * time.Millisecond,
+	})
+	<-samples
+
+	if err := fake.SetProperties("foo.service", "Service", map[string]any{
+		"CPUUsageNSec": uint64(2 * time.Second),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var s sd.ResourceSample
+	for s.CPUTime == 0 {
+		s = (<-samples)[0]
+		if s.Err != nil || s.Reset {
+			t.Fatalf("unexpected sample %+v", s)
+		}
+	}
+	if s.IOReadRate != 0 || s.IOWriteRate != 0 || s.IPIngressRate != 0 || s.IPEgressRate != 0 {
+		t.Errorf("missing counters have rates: %+v", s)
+	}
+
+	// The counter going backwards without a restart is a reset, too.
+	if err := fake.SetProperties("foo.service", "Service", map[string]any{
+		"CPUUsageNSec": uint64(time.Millisecond),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for !s.Reset {
+		s = (<-samples)[0]
+		if s.Err != nil {
+			t.Fatal(s.Err)
+		}
+	}
+	if s.CPUTime != 0 || s.Elapsed != 0 {
+		t.Errorf("reset sample has deltas: %+v", s)
+	}
+
+	cancel()
+	for range samples {
+	}
+}
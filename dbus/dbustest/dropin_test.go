@@ -0,0 +1,168 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/dbus/dbustest"
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// waitReload waits for the manager to finish a reload.
+func waitReload(t *testing.T, events <-chan sd.Event) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			if r, ok := e.(*sd.ReloadingEvent); ok && !r.Active {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for a reload")
+		}
+	}
+}
+
+func TestDropIns(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := conn.Events(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	persistent := sd.DropInOptions{Root: root}
+	runtime := sd.DropInOptions{Root: root, Runtime: true}
+	limits := []*unit.UnitSection{{
+		Section: "Service",
+		Entries: []*unit.UnitEntry{{Name: "MemoryMax", Value: "1G"}, {Name: "TasksMax", Value: "100"}},
+	}}
+
+	p, err := conn.WriteDropIn(ctx, "foo.service", "50-limits", limits, persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitReload(t, events)
+	if want := filepath.Join(root, "etc/systemd/system/foo.service.d/50-limits.conf"); p != want {
+		t.Errorf("WriteDropIn() = %s, want %s", p, want)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil || string(data) != "[Service]\nMemoryMax=1G\nTasksMax=100\n" {
+		t.Errorf("unexpected drop-in %q (%v)", data, err)
+	}
+
+	if _, err := conn.WriteDropIn(ctx, "foo.service", "override.conf", limits[:0], persistent); err != nil {
+		t.Fatal(err)
+	}
+	waitReload(t, events)
+	if _, err := conn.WriteDropIn(ctx, "foo.service", "debug", limits, runtime); err != nil {
+		t.Fatal(err)
+	}
+	waitReload(t, events)
+
+	dropIns, err := conn.ListDropIns("foo.service", persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropIns) != 2 || dropIns[0].Name != "50-limits.conf" || dropIns[1].Name != "override.conf" {
+		t.Fatalf("unexpected drop-ins %+v", dropIns)
+	}
+	if !reflect.DeepEqual(dropIns[0].Sections, limits) {
+		t.Errorf("unexpected sections %v", dropIns[0].Sections)
+	}
+
+	if err := conn.RemoveDropIn(ctx, "foo.service", "override", persistent); err != nil {
+		t.Fatal(err)
+	}
+	waitReload(t, events)
+	if dropIns, err := conn.ListDropIns("foo.service", persistent); err != nil || len(dropIns) != 1 {
+		t.Errorf("after removing: drop-ins %+v (%v)", dropIns, err)
+	}
+
+	removed, err := conn.RevertDropIns(ctx, "foo.service", persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitReload(t, events)
+	if len(removed) != 2 {
+		t.Errorf("RevertDropIns() = %v, want the persistent and the runtime drop-in", removed)
+	}
+	for _, dir := range []string{"etc/systemd/system/foo.service.d", "run/systemd/system/foo.service.d"} {
+		if _, err := os.Stat(filepath.Join(root, dir)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed: %v", dir, err)
+		}
+	}
+
+	for _, name := range []string{"", "../escape", ".hidden"} {
+		if _, err := conn.WriteDropIn(ctx, "foo.service", name, limits, persistent); err == nil {
+			t.Errorf("writing drop-in %q succeeded", name)
+		}
+	}
+}
+
+// TestRevertDropIns checks that reverting removes the persistent and the
+// runtime drop-ins of a unit, and nothing else.
+func TestRevertDropIns(t *testing.T) {
+	_, conn := setup(t, dbustest.Unit{Name: "foo.service"}, dbustest.Unit{Name: "bar.service"})
+	ctx := context.Background()
+
+	root := t.TempDir()
+	persistent := sd.DropInOptions{Root: root}
+	runtime := sd.DropInOptions{Root: root, Runtime: true}
+	section := []*unit.UnitSection{{
+		Section: "Unit",
+		Entries: []*unit.UnitEntry{{Name: "Description", Value: "override"}},
+	}}
+
+	var want []string
+	for _, opts := range []sd.DropInOptions{persistent, runtime} {
+		p, err := conn.WriteDropIn(ctx, "foo.service", "override", section, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, p)
+	}
+	other, err := conn.WriteDropIn(ctx, "bar.service", "override", section, persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := conn.RevertDropIns(ctx, "foo.service", persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("RevertDropIns() = %v, want %v", removed, want)
+	}
+	for _, opts := range []sd.DropInOptions{persistent, runtime} {
+		if dropIns, err := conn.ListDropIns("foo.service", opts); err != nil || len(dropIns) != 0 {
+			t.Errorf("drop-ins left after reverting: %+v (%v)", dropIns, err)
+		}
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("drop-in of another unit removed: %v", err)
+	}
+}
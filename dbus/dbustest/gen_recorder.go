@@ -0,0 +1,232 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+
+// gen_recorder generates the methods of the Recorder from the Manager
+// interface in ../interfaces.go. Methods that need a hand-written mock are
+// listed in handWritten and implemented in recorder.go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// handWritten are the methods implemented in recorder.go.
+var handWritten = map[string]bool{
+	"DumpByFileDescriptor":        true,
+	"Events":                      true,
+	"SubscribeUnitsCustomContext": true,
+	"Connected":                   true,
+	"Close":                       true,
+}
+
+const header = `// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_recorder.go; DO NOT EDIT.
+
+package dbustest
+
+import (
+	"context"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+)
+`
+
+func main() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "../interfaces.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ifaces := make(map[string]*ast.InterfaceType)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				ifaces[ts.Name.Name] = it
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	var walk func(name string)
+	walk = func(name string) {
+		it, ok := ifaces[name]
+		if !ok {
+			log.Fatalf("interface %s not found", name)
+		}
+		for _, m := range it.Methods.List {
+			if len(m.Names) == 0 {
+				walk(m.Type.(*ast.Ident).Name)
+				continue
+			}
+			if !handWritten[m.Names[0].Name] {
+				method(&buf, m.Names[0].Name, m.Type.(*ast.FuncType))
+			}
+		}
+	}
+	walk("Manager")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile("recorder_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// qualify prefixes the types of package dbus used in expr with sd.
+func qualify(expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			return false
+		case *ast.Ident:
+			if unicode.IsUpper(rune(n.Name[0])) {
+				n.Name = "sd." + n.Name
+			}
+		}
+		return true
+	})
+}
+
+func exprString(expr ast.Expr) string {
+	var b strings.Builder
+	if err := format.Node(&b, token.NewFileSet(), expr); err != nil {
+		log.Fatal(err)
+	}
+	return b.String()
+}
+
+// zero returns the zero value of the type t.
+func zero(t string) string {
+	switch {
+	case t == "string":
+		return `""`
+	case t == "bool":
+		return "false"
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint"):
+		return "0"
+	default:
+		return "nil"
+	}
+}
+
+// jobType returns the type of the jobs enqueued by method.
+func jobType(method string, params []string) string {
+	for _, p := range params {
+		if p == "jobType" {
+			return "jobType"
+		}
+	}
+	t := strings.TrimSuffix(method, "UnitJob")
+	if t == method || strings.HasPrefix(t, "StartTransient") {
+		return `"start"`
+	}
+	var b strings.Builder
+	for i, r := range t {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return fmt.Sprintf("%q", b.String())
+}
+
+// method writes the Recorder method name of type ft to buf.
+func method(buf *bytes.Buffer, name string, ft *ast.FuncType) {
+	qualify(ft)
+
+	// The context and the job channel are not recorded.
+	var params, recorded, passed []string
+	hasCh := false
+	for i, field := range ft.Params.List {
+		_, variadic := field.Type.(*ast.Ellipsis)
+		for _, n := range field.Names {
+			params = append(params, n.Name)
+			if variadic {
+				passed = append(passed, n.Name+"...")
+			} else {
+				passed = append(passed, n.Name)
+			}
+			if i == 0 {
+				continue
+			}
+			if n.Name == "ch" {
+				hasCh = true
+				continue
+			}
+			recorded = append(recorded, n.Name)
+		}
+	}
+
+	var zeros, mocks []string
+	for _, field := range ft.Results.List {
+		t := exprString(field.Type)
+		switch {
+		case t == "error":
+			continue
+		case t == "sd.Job":
+			mocks = append(mocks, fmt.Sprintf("r.newJob(name, %s)", jobType(name, params)))
+		case strings.HasPrefix(t, "*"):
+			mocks = append(mocks, "&"+t[1:]+"{}")
+		case strings.HasPrefix(t, "map["):
+			mocks = append(mocks, t+"{}")
+		default:
+			mocks = append(mocks, zero(t))
+		}
+		zeros = append(zeros, zero(t))
+	}
+
+	sig := strings.TrimPrefix(exprString(ft), "func")
+	fmt.Fprintf(buf, "\nfunc (r *Recorder) %s%s {\n", name, sig)
+	fmt.Fprintf(buf, "\tif err := r.record(%s); err != nil {\n", strings.Join(append([]string{fmt.Sprintf("%q", name)}, recorded...), ", "))
+	fmt.Fprintf(buf, "\t\treturn %s\n\t}\n", strings.Join(append(zeros, "err"), ", "))
+	fmt.Fprintf(buf, "\tif r.Next != nil {\n\t\treturn r.Next.%s(%s)\n\t}\n", name, strings.Join(passed, ", "))
+	if hasCh {
+		fmt.Fprintf(buf, "\treturn r.mockJob(ch)\n}\n")
+	} else {
+		fmt.Fprintf(buf, "\treturn %s\n}\n", strings.Join(append(mocks, "nil"), ", "))
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// jobModes are the job modes accepted by systemd.
+var jobModes = map[string]bool{
+	"replace":              true,
+	"fail":                 true,
+	"isolate":              true,
+	"ignore-dependencies":  true,
+	"ignore-requirements":  true,
+	"flush":                true,
+	"triggering":           true,
+	"restart-dependencies": true,
+	"replace-irreversibly": true,
+}
+
+type job struct {
+	id      uint32
+	path    dbus.ObjectPath
+	unit    *unit
+	jobType string
+	state   string
+	cancel  chan struct{}
+}
+
+// jobRef is the (uo) struct referencing a job in the Job property of units.
+type jobRef struct {
+	Id   uint32
+	Path dbus.ObjectPath
+}
+
+// unitRef is the (so) struct referencing a unit in the Unit property of
+// jobs.
+type unitRef struct {
+	Name string
+	Path dbus.ObjectPath
+}
+
+// enqueue adds a job of jobType for u, and starts running it after the job
+// delay. The caller must hold s.mu.
+func (s *Systemd) enqueue(u *unit, jobType, mode string) (*job, *dbus.Error) {
+	if !jobModes[mode] {
+		return nil, invalidArgs("Job mode %s invalid.", mode)
+	}
+	switch u.loadState() {
+	case "loaded":
+	case "masked":
+		if jobType != "stop" {
+			return nil, newError("org.freedesktop.systemd1.UnitMasked", "Unit %s is masked.", u.name)
+		}
+	default:
+		return nil, noSuchUnit("Unit %s not found.", u.name)
+	}
+
+	if j := u.job; j != nil {
+		if j.jobType == jobType {
+			return j, nil
+		}
+		if mode == "fail" {
+			return nil, newError("org.freedesktop.systemd1.TransactionIsDestructive",
+				"Transaction for %s/%s is destructive (%s has '%s' job queued, but '%s' is included in transaction).",
+				u.name, jobType, u.name, j.jobType, jobType)
+		}
+		s.finish(j, "canceled")
+	}
+
+	j := &job{
+		id:      s.nextJob,
+		unit:    u,
+		jobType: jobType,
+		state:   "waiting",
+		cancel:  make(chan struct{}),
+	}
+	s.nextJob++
+	j.path = dbus.ObjectPath(jobPrefix + strconv.FormatUint(uint64(j.id), 10))
+	s.jobs[j.id] = j
+	u.job = j
+
+	s.emit(managerPath, "org.freedesktop.systemd1.Manager.JobNew", j.id, j.path, u.name)
+	s.emitJobChanged(u)
+
+	delay := s.jobDelay
+	go func() {
+		select {
+		case <-time.After(delay):
+		case <-j.cancel:
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.jobs[j.id] != j || s.closed {
+			return
+		}
+		j.state = "running"
+		s.finish(j, s.run(j))
+	}()
+
+	return j, nil
+}
+
+// run performs the state changes of j and returns the job result. The
+// caller must hold s.mu.
+func (s *Systemd) run(j *job) string {
+	u := j.unit
+	active := !isInactive(u.activeState())
+
+	switch j.jobType {
+	case "start":
+		return s.start(u)
+	case "stop":
+		s.stop(u)
+	case "restart":
+		s.stop(u)
+		return s.start(u)
+	case "try-restart":
+		if active {
+			s.stop(u)
+			return s.start(u)
+		}
+	case "reload":
+		if !active {
+			return "invalid"
+		}
+		s.setState(u, "reloading", "reload")
+		s.setState(u, "active", activeSubState(unitType(u.name)))
+	}
+	return "done"
+}
+
+func (s *Systemd) start(u *unit) string {
+	if u.activeState() == "active" {
+		return "done"
+	}
+	if unitType(u.name) == "service" {
+		u.set("org.freedesktop.systemd1.Service", map[string]any{
+			"Result":         "success",
+			"ExecMainCode":   int32(0),
+			"ExecMainStatus": int32(0),
+		})
+	}
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	u.set(unitInterface, map[string]any{"InvocationID": id})
+
+	s.setState(u, "activating", "start")
+	result := orDefault(u.startResult, "done")
+	if result != "done" {
+		s.setState(u, "failed", "failed")
+		return result
+	}
+	s.setState(u, "active", activeSubState(unitType(u.name)))
+	return result
+}
+
+func (s *Systemd) stop(u *unit) {
+	if isInactive(u.activeState()) {
+		return
+	}
+	s.setState(u, "deactivating", "stop")
+	s.setState(u, "inactive", "dead")
+}
+
+// finish removes j with result and emits JobRemoved. The caller must hold
+// s.mu.
+func (s *Systemd) finish(j *job, result string) {
+	close(j.cancel)
+	delete(s.jobs, j.id)
+	u := j.unit
+	u.job = nil
+
+	s.emitJobChanged(u)
+	s.emit(managerPath, "org.freedesktop.systemd1.Manager.JobRemoved", j.id, j.path, u.name, result)
+	s.collect(u)
+}
+
+func (s *Systemd) emitJobChanged(u *unit) {
+	ref := jobRef{0, "/"}
+	if u.job != nil {
+		ref = jobRef{u.job.id, u.job.path}
+	}
+	changed := map[string]any{"Job": ref}
+	u.set(unitInterface, changed)
+	s.emitPropertiesChanged(u, unitInterface, changed)
+}
+
+// cancelJob cancels the job id. The caller must hold s.mu.
+func (s *Systemd) cancelJob(id uint32) *dbus.Error {
+	j, ok := s.jobs[id]
+	if !ok {
+		return noSuchJob(id)
+	}
+	s.finish(j, "canceled")
+	return nil
+}
+
+func newError(name, format string, args ...any) *dbus.Error {
+	return dbus.NewError(name, []any{fmt.Sprintf(format, args...)})
+}
+
+func invalidArgs(format string, args ...any) *dbus.Error {
+	return newError("org.freedesktop.DBus.Error.InvalidArgs", format, args...)
+}
+
+func noSuchUnit(format string, args ...any) *dbus.Error {
+	return newError("org.freedesktop.systemd1.NoSuchUnit", format, args...)
+}
+
+func noSuchJob(id uint32) *dbus.Error {
+	return newError("org.freedesktop.systemd1.NoSuchJob", "Job %d does not exist.", id)
+}
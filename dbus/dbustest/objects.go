@@ -0,0 +1,889 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest
+
+import (
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+)
+
+// The exported methods of the types in this file are the D-Bus methods of
+// the objects.
+
+// unitTypes are the known unit types.
+var unitTypes = map[string]bool{
+	"service":   true,
+	"socket":    true,
+	"target":    true,
+	"device":    true,
+	"mount":     true,
+	"automount": true,
+	"swap":      true,
+	"timer":     true,
+	"path":      true,
+	"slice":     true,
+	"scope":     true,
+}
+
+// unitProperties are the properties of the org.freedesktop.systemd1.Unit
+// interface that can be set on transient units. Other properties are set on
+// the interface of the unit type.
+var unitProperties = map[string]bool{
+	"Description":         true,
+	"Documentation":       true,
+	"Wants":               true,
+	"Requires":            true,
+	"Requisite":           true,
+	"BindsTo":             true,
+	"PartOf":              true,
+	"Upholds":             true,
+	"Conflicts":           true,
+	"Before":              true,
+	"After":               true,
+	"OnFailure":           true,
+	"OnSuccess":           true,
+	"CollectMode":         true,
+	"DefaultDependencies": true,
+	"SourcePath":          true,
+	"StopWhenUnneeded":    true,
+	"RefuseManualStart":   true,
+	"RefuseManualStop":    true,
+	"JobTimeoutUSec":      true,
+	"Markers":             true,
+}
+
+// auxUnit is an auxiliary unit of StartTransientUnit.
+type auxUnit struct {
+	Name       string
+	Properties []sd.Property
+}
+
+// installChange is a change reported by the unit file methods.
+type installChange struct {
+	Type        string
+	Filename    string
+	Destination string
+}
+
+func validUnitName(name string) bool {
+	i := strings.LastIndex(name, ".")
+	return i > 0 && unitTypes[name[i+1:]] && !strings.Contains(name, "/")
+}
+
+func objectPath(msg dbus.Message) dbus.ObjectPath {
+	p, _ := msg.Headers[dbus.FieldPath].Value().(dbus.ObjectPath)
+	return p
+}
+
+// bus implements the match methods of the bus, so that adding matches
+// succeeds. All signals are sent to all connections.
+type bus struct{}
+
+func (*bus) AddMatch(rule string) *dbus.Error {
+	return nil
+}
+
+func (*bus) RemoveMatch(rule string) *dbus.Error {
+	return nil
+}
+
+// manager is the org.freedesktop.systemd1.Manager interface.
+type manager struct {
+	s *Systemd
+}
+
+func (m *manager) GetUnit(name string) (dbus.ObjectPath, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u, ok := m.s.units[name]
+	if !ok {
+		return "", noSuchUnit("Unit %s not loaded.", name)
+	}
+	return u.path, nil
+}
+
+func (m *manager) LoadUnit(name string) (dbus.ObjectPath, *dbus.Error) {
+	if !validUnitName(name) {
+		return "", invalidArgs("Unit name %s is not valid.", name)
+	}
+
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	return m.s.loadUnit(name, "not-found").path, nil
+}
+
+func (m *manager) GetJob(id uint32) (dbus.ObjectPath, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	j, ok := m.s.jobs[id]
+	if !ok {
+		return "", noSuchJob(id)
+	}
+	return j.path, nil
+}
+
+func (m *manager) listUnits(match func(*unit) bool) []sd.UnitStatus {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	status := []sd.UnitStatus{}
+	for _, name := range slices.Sorted(maps.Keys(m.s.units)) {
+		if u := m.s.units[name]; match(u) {
+			status = append(status, u.status())
+		}
+	}
+	return status
+}
+
+func (m *manager) ListUnits() ([]sd.UnitStatus, *dbus.Error) {
+	return m.listUnits(func(*unit) bool { return true }), nil
+}
+
+func (m *manager) ListUnitsFiltered(states []string) ([]sd.UnitStatus, *dbus.Error) {
+	return m.ListUnitsByPatterns(states, nil)
+}
+
+func (m *manager) ListUnitsByPatterns(states, patterns []string) ([]sd.UnitStatus, *dbus.Error) {
+	return m.listUnits(func(u *unit) bool {
+		st := u.status()
+		if len(states) > 0 && !slices.Contains(states, st.LoadState) &&
+			!slices.Contains(states, st.ActiveState) && !slices.Contains(states, st.SubState) {
+			return false
+		}
+		if len(patterns) == 0 {
+			return true
+		}
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, u.name); ok {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (m *manager) ListUnitsByNames(names []string) ([]sd.UnitStatus, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	status := []sd.UnitStatus{}
+	for _, name := range names {
+		if !validUnitName(name) {
+			return nil, invalidArgs("Unit name %s is not valid.", name)
+		}
+		status = append(status, m.s.loadUnit(name, "not-found").status())
+	}
+	return status, nil
+}
+
+func (m *manager) ListJobs() ([]sd.JobStatus, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	jobs := []sd.JobStatus{}
+	for _, id := range slices.Sorted(maps.Keys(m.s.jobs)) {
+		j := m.s.jobs[id]
+		jobs = append(jobs, sd.JobStatus{
+			Id:       j.id,
+			Unit:     j.unit.name,
+			JobType:  j.jobType,
+			Status:   j.state,
+			JobPath:  j.path,
+			UnitPath: j.unit.path,
+		})
+	}
+	return jobs, nil
+}
+
+// enqueue enqueues a job for the unit name. jobType may depend on the state
+// of the unit.
+func (m *manager) enqueue(name, mode string, jobType func(active bool) string) (dbus.ObjectPath, *dbus.Error) {
+	if !validUnitName(name) {
+		return "", invalidArgs("Unit name %s is not valid.", name)
+	}
+
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u := m.s.loadUnit(name, "not-found")
+	j, err := m.s.enqueue(u, jobType(!isInactive(u.activeState())), mode)
+	if err != nil {
+		return "", err
+	}
+	return j.path, nil
+}
+
+func always(jobType string) func(bool) string {
+	return func(bool) string { return jobType }
+}
+
+func (m *manager) StartUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, always("start"))
+}
+
+func (m *manager) StopUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, always("stop"))
+}
+
+func (m *manager) RestartUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, always("restart"))
+}
+
+func (m *manager) ReloadUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, always("reload"))
+}
+
+func (m *manager) TryRestartUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, always("try-restart"))
+}
+
+func (m *manager) ReloadOrRestartUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, func(active bool) string {
+		if active {
+			return "reload"
+		}
+		return "start"
+	})
+}
+
+func (m *manager) ReloadOrTryRestartUnit(name, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return m.enqueue(name, mode, func(active bool) string {
+		if active {
+			return "reload"
+		}
+		return "try-restart"
+	})
+}
+
+func (m *manager) KillUnit(name, who string, signal int32) *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if _, ok := m.s.units[name]; !ok {
+		return noSuchUnit("Unit %s not loaded.", name)
+	}
+	return nil
+}
+
+func (m *manager) AttachProcessesToUnit(name, subcgroup string, pids []uint32) *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u, ok := m.s.units[name]
+	if !ok {
+		return noSuchUnit("Unit %s not loaded.", name)
+	}
+	if u.activeState() != "active" {
+		return newError("org.freedesktop.systemd1.UnitInactive", "Unit %s is not active.", name)
+	}
+	return nil
+}
+
+func (m *manager) ResetFailedUnit(name string) *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u, ok := m.s.units[name]
+	if !ok {
+		return noSuchUnit("Unit %s not loaded.", name)
+	}
+	m.s.resetFailed(u)
+	return nil
+}
+
+func (m *manager) ResetFailed() *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for _, u := range m.s.units {
+		m.s.resetFailed(u)
+	}
+	return nil
+}
+
+func (m *manager) SetUnitProperties(name string, runtime bool, props []sd.Property) *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u, ok := m.s.units[name]
+	if !ok {
+		return noSuchUnit("Unit %s not loaded.", name)
+	}
+	m.s.setProperties(u, props)
+	return nil
+}
+
+func (m *manager) StartTransientUnit(name, mode string, props []sd.Property, aux []auxUnit) (dbus.ObjectPath, *dbus.Error) {
+	names := []string{name}
+	for _, a := range aux {
+		names = append(names, a.Name)
+	}
+	for _, n := range names {
+		if !validUnitName(n) {
+			return "", invalidArgs("Invalid unit name or type.")
+		}
+	}
+	if !jobModes[mode] {
+		return "", invalidArgs("Job mode %s invalid.", mode)
+	}
+
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	for _, n := range names {
+		if u, ok := m.s.units[n]; ok && u.loadState() != "not-found" {
+			return "", newError("org.freedesktop.systemd1.UnitExists", "Unit %s already exists.", n)
+		}
+	}
+
+	for _, a := range aux {
+		m.s.addTransient(a.Name, a.Properties)
+	}
+	u := m.s.addTransient(name, props)
+
+	j, err := m.s.enqueue(u, "start", mode)
+	if err != nil {
+		return "", err
+	}
+	return j.path, nil
+}
+
+func (m *manager) CancelJob(id uint32) *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	return m.s.cancelJob(id)
+}
+
+func (m *manager) Subscribe() *dbus.Error {
+	return nil
+}
+
+func (m *manager) Unsubscribe() *dbus.Error {
+	return nil
+}
+
+func (m *manager) Reload() *dbus.Error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	m.s.emit(managerPath, "org.freedesktop.systemd1.Manager.Reloading", true)
+	m.s.emit(managerPath, "org.freedesktop.systemd1.Manager.Reloading", false)
+	return nil
+}
+
+func (m *manager) ListUnitFiles() ([]sd.UnitFile, *dbus.Error) {
+	return m.ListUnitFilesByPatterns(nil, nil)
+}
+
+func (m *manager) ListUnitFilesByPatterns(states, patterns []string) ([]sd.UnitFile, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	files := []sd.UnitFile{}
+	for _, name := range slices.Sorted(maps.Keys(m.s.units)) {
+		state := m.s.units[name].unitFileState()
+		if state == "" || len(states) > 0 && !slices.Contains(states, state) {
+			continue
+		}
+		matched := len(patterns) == 0
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = true
+			}
+		}
+		if matched {
+			files = append(files, sd.UnitFile{Path: unitFilePath(name), Type: state})
+		}
+	}
+	return files, nil
+}
+
+func (m *manager) GetUnitFileState(file string) (string, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	u, err := m.s.unitFile(file)
+	if err != nil {
+		return "", err
+	}
+	return u.unitFileState(), nil
+}
+
+func (m *manager) EnableUnitFiles(files []string, runtime, force bool) (bool, []installChange, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	state, dir := "enabled", "/etc/systemd/system/"
+	if runtime {
+		state, dir = "enabled-runtime", "/run/systemd/system/"
+	}
+
+	changes := []installChange{}
+	for _, file := range files {
+		u, err := m.s.unitFile(file)
+		if err != nil {
+			return false, nil, err
+		}
+		switch u.unitFileState() {
+		case "masked", "masked-runtime":
+			return false, nil, newError("org.freedesktop.systemd1.UnitMasked", "Unit file %s is masked.", u.name)
+		case state:
+			continue
+		}
+		m.s.setUnitFileState(u, state)
+		changes = append(changes, installChange{"symlink", dir + "multi-user.target.wants/" + u.name, unitFilePath(u.name)})
+	}
+
+	m.s.emit(managerPath, "org.freedesktop.systemd1.Manager.UnitFilesChanged")
+	return true, changes, nil
+}
+
+func (m *manager) DisableUnitFiles(files []string, runtime bool) ([]installChange, *dbus.Error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	dir := "/etc/systemd/system/"
+	if runtime {
+		dir = "/run/systemd/system/"
+	}
+
+	changes := []installChange{}
+	for _, file := range files {
+		u, err := m.s.unitFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(u.unitFileState(), "enabled") {
+			continue
+		}
+		m.s.setUnitFileState(u, "disabled")
+		changes = append(changes, installChange{"unlink", dir + "multi-user.target.wants/" + u.name, ""})
+	}
+
+	m.s.emit(managerPath, "org.freedesktop.systemd1.Manager.UnitFilesChanged")
+	return changes, nil
+}
+
+// unitObject is the org.freedesktop.systemd1.Unit interface of the unit
+// objects.
+type unitObject struct {
+	s *Systemd
+}
+
+// enqueue enqueues a job for the unit at the path of msg.
+func (o *unitObject) enqueue(msg dbus.Message, jobType, mode string) (dbus.ObjectPath, *dbus.Error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	u, err := o.s.unitAt(objectPath(msg))
+	if err != nil {
+		return "", err
+	}
+	j, err := o.s.enqueue(u, jobType, mode)
+	if err != nil {
+		return "", err
+	}
+	return j.path, nil
+}
+
+func (o *unitObject) Start(msg dbus.Message, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return o.enqueue(msg, "start", mode)
+}
+
+func (o *unitObject) Stop(msg dbus.Message, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return o.enqueue(msg, "stop", mode)
+}
+
+func (o *unitObject) Restart(msg dbus.Message, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return o.enqueue(msg, "restart", mode)
+}
+
+func (o *unitObject) Reload(msg dbus.Message, mode string) (dbus.ObjectPath, *dbus.Error) {
+	return o.enqueue(msg, "reload", mode)
+}
+
+func (o *unitObject) ResetFailed(msg dbus.Message) *dbus.Error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	u, err := o.s.unitAt(objectPath(msg))
+	if err != nil {
+		return err
+	}
+	o.s.resetFailed(u)
+	return nil
+}
+
+func (o *unitObject) Ref(msg dbus.Message) *dbus.Error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	u, err := o.s.unitAt(objectPath(msg))
+	if err != nil {
+		return err
+	}
+	u.refs++
+	return nil
+}
+
+func (o *unitObject) Unref(msg dbus.Message) *dbus.Error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	u, err := o.s.unitAt(objectPath(msg))
+	if err != nil {
+		return err
+	}
+	if u.refs > 0 {
+		u.refs--
+	}
+	o.s.collect(u)
+	return nil
+}
+
+// jobObject is the org.freedesktop.systemd1.Job interface of the job
+// objects.
+type jobObject struct {
+	s *Systemd
+}
+
+func (o *jobObject) Cancel(msg dbus.Message) *dbus.Error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	j, err := o.s.jobAt(objectPath(msg))
+	if err != nil {
+		return err
+	}
+	return o.s.cancelJob(j.id)
+}
+
+func (o *jobObject) GetAfter(msg dbus.Message) ([]sd.JobStatus, *dbus.Error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	if _, err := o.s.jobAt(objectPath(msg)); err != nil {
+		return nil, err
+	}
+	return []sd.JobStatus{}, nil
+}
+
+func (o *jobObject) GetBefore(msg dbus.Message) ([]sd.JobStatus, *dbus.Error) {
+	return o.GetAfter(msg)
+}
+
+// properties is the org.freedesktop.DBus.Properties interface of all
+// objects.
+type properties struct {
+	s *Systemd
+}
+
+// props returns the properties of iface of the object at p. The caller must
+// hold s.mu.
+func (o *properties) props(p dbus.ObjectPath, iface string) (map[string]dbus.Variant, *dbus.Error) {
+	switch {
+	case p == managerPath:
+		if iface != "org.freedesktop.systemd1.Manager" {
+			break
+		}
+		return map[string]dbus.Variant{
+			"Version":     dbus.MakeVariant("dbustest"),
+			"Features":    dbus.MakeVariant(""),
+			"SystemState": dbus.MakeVariant("running"),
+			"NNames":      dbus.MakeVariant(uint32(len(o.s.units))),
+			"NJobs":       dbus.MakeVariant(uint32(len(o.s.jobs))),
+			"Environment": dbus.MakeVariant([]string{}),
+		}, nil
+
+	case strings.HasPrefix(string(p), unitPrefix):
+		u, err := o.s.unitAt(p)
+		if err != nil {
+			return nil, err
+		}
+		props, ok := u.props[iface]
+		if !ok && iface != typeInterface(u.name) {
+			break
+		}
+		return props, nil
+
+	case strings.HasPrefix(string(p), jobPrefix):
+		j, err := o.s.jobAt(p)
+		if err != nil {
+			return nil, err
+		}
+		if iface != "org.freedesktop.systemd1.Job" {
+			break
+		}
+		return map[string]dbus.Variant{
+			"Id":      dbus.MakeVariant(j.id),
+			"Unit":    dbus.MakeVariant(unitRef{j.unit.name, j.unit.path}),
+			"JobType": dbus.MakeVariant(j.jobType),
+			"State":   dbus.MakeVariant(j.state),
+		}, nil
+
+	default:
+		return nil, newError("org.freedesktop.DBus.Error.UnknownObject", "Unknown object '%s'.", p)
+	}
+
+	return nil, newError("org.freedesktop.DBus.Error.UnknownInterface", "Unknown interface %s.", iface)
+}
+
+func (o *properties) Get(msg dbus.Message, iface, property string) (dbus.Variant, *dbus.Error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	props, err := o.props(objectPath(msg), iface)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	v, ok := props[property]
+	if !ok {
+		return dbus.Variant{}, newError("org.freedesktop.DBus.Error.UnknownProperty", "Unknown property %s.", property)
+	}
+	return v, nil
+}
+
+func (o *properties) GetAll(msg dbus.Message, iface string) (map[string]dbus.Variant, *dbus.Error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+
+	props, err := o.props(objectPath(msg), iface)
+	if err != nil {
+		return nil, err
+	}
+	return maps.Clone(props), nil
+}
+
+func (o *properties) Set(msg dbus.Message, iface, property string, value dbus.Variant) *dbus.Error {
+	return newError("org.freedesktop.DBus.Error.PropertyReadOnly", "Property %s is read-only.", property)
+}
+
+// unitAt returns the unit at the object path p. The caller must hold s.mu.
+func (s *Systemd) unitAt(p dbus.ObjectPath) (*unit, *dbus.Error) {
+	u, ok := s.paths[p]
+	if !ok {
+		return nil, newError("org.freedesktop.DBus.Error.UnknownObject", "Unknown object '%s'.", p)
+	}
+	return u, nil
+}
+
+// jobAt returns the job at the object path p. The caller must hold s.mu.
+func (s *Systemd) jobAt(p dbus.ObjectPath) (*job, *dbus.Error) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(p), jobPrefix), 10, 32)
+	if err == nil {
+		if j, ok := s.jobs[uint32(id)]; ok {
+			return j, nil
+		}
+	}
+	return nil, newError("org.freedesktop.DBus.Error.UnknownObject", "Unknown object '%s'.", p)
+}
+
+// unitFile returns the unit with a unit file for file, which is a unit name
+// or path. The caller must hold s.mu.
+func (s *Systemd) unitFile(file string) (*unit, *dbus.Error) {
+	u, ok := s.units[path.Base(file)]
+	if !ok || u.unitFileState() == "" {
+		return nil, noSuchUnit("Unit file %s does not exist.", path.Base(file))
+	}
+	return u, nil
+}
+
+// addTransient adds the transient unit name with props. The caller must
+// hold s.mu.
+func (s *Systemd) addTransient(name string, props []sd.Property) *unit {
+	u := s.loadUnit(name, "loaded")
+	u.transient = true
+	u.set(unitInterface, map[string]any{"LoadState": "loaded", "Transient": true})
+	s.setProperties(u, props)
+	return u
+}
+
+// setProperties sets props on the unit u, on the Unit interface or the
+// interface of the unit type. The caller must hold s.mu.
+func (s *Systemd) setProperties(u *unit, props []sd.Property) {
+	changed := map[string]map[string]any{}
+	for _, p := range props {
+		if p.Name == "AddRef" {
+			if ref, _ := p.Value.Value().(bool); ref {
+				u.refs++
+			}
+			continue
+		}
+
+		iface := typeInterface(u.name)
+		if unitProperties[p.Name] {
+			iface = unitInterface
+		}
+		if changed[iface] == nil {
+			changed[iface] = map[string]any{}
+		}
+		changed[iface][p.Name] = retype(execStatus(p.Value))
+	}
+
+	for iface, props := range changed {
+		u.set(iface, props)
+		s.emitPropertiesChanged(u, iface, props)
+	}
+}
+
+// resetFailed resets the failed state of u. The caller must hold s.mu.
+func (s *Systemd) resetFailed(u *unit) {
+	if u.activeState() != "failed" {
+		return
+	}
+	s.setState(u, "inactive", "dead")
+	s.collect(u)
+}
+
+func (s *Systemd) setUnitFileState(u *unit, state string) {
+	changed := map[string]any{"UnitFileState": state}
+	u.set(unitInterface, changed)
+	s.emitPropertiesChanged(u, unitInterface, changed)
+}
+
+func (u *unit) unitFileState() string {
+	s, _ := u.props[unitInterface]["UnitFileState"].Value().(string)
+	return s
+}
+
+func unitFilePath(name string) string {
+	return "/usr/lib/systemd/system/" + name
+}
+
+// execCommand is an entry of the Exec* properties of services, as read.
+type execCommand struct {
+	Path                    string
+	Args                    []string
+	IgnoreErrors            bool
+	StartTimestamp          uint64
+	StartTimestampMonotonic uint64
+	ExitTimestamp           uint64
+	ExitTimestampMonotonic  uint64
+	PID                     uint32
+	Code                    int32
+	Status                  int32
+}
+
+// execStatus converts the commands of Exec* properties, which are set as
+// a(sasb), to the a(sasbttttuii) they are read as.
+func execStatus(v dbus.Variant) dbus.Variant {
+	if v.Signature().String() != "a(sasb)" {
+		return v
+	}
+
+	var set []struct {
+		Path         string
+		Args         []string
+		IgnoreErrors bool
+	}
+	if dbus.Store([]any{v.Value()}, &set) != nil {
+		return v
+	}
+	cmds := make([]execCommand, len(set))
+	for i, c := range set {
+		cmds[i] = execCommand{Path: c.Path, Args: c.Args, IgnoreErrors: c.IgnoreErrors}
+	}
+	return dbus.MakeVariant(cmds)
+}
+
+// retype returns v with structs converted from the []any godbus decodes
+// them to back to structs, as godbus can only encode them from Go structs.
+func retype(v dbus.Variant) dbus.Variant {
+	sig := v.Signature().String()
+	if !strings.Contains(sig, "(") {
+		return v
+	}
+
+	t, rest := typeOf(sig)
+	if t == nil || rest != "" {
+		return v
+	}
+	p := reflect.New(t)
+	if err := dbus.Store([]any{v.Value()}, p.Interface()); err != nil {
+		return v
+	}
+	return dbus.MakeVariantWithSignature(p.Elem().Interface(), v.Signature())
+}
+
+// typeOf returns the Go type of the first complete type in the signature
+// sig, and the rest of the signature.
+func typeOf(sig string) (reflect.Type, string) {
+	if sig == "" {
+		return nil, ""
+	}
+
+	switch sig[0] {
+	case 'a':
+		if strings.HasPrefix(sig, "a{") {
+			k, rest := typeOf(sig[2:])
+			v, rest := typeOf(rest)
+			if k == nil || v == nil || !strings.HasPrefix(rest, "}") {
+				return nil, ""
+			}
+			return reflect.MapOf(k, v), rest[1:]
+		}
+		elem, rest := typeOf(sig[1:])
+		if elem == nil {
+			return nil, ""
+		}
+		return reflect.SliceOf(elem), rest
+
+	case '(':
+		var fields []reflect.StructField
+		rest := sig[1:]
+		for !strings.HasPrefix(rest, ")") {
+			var t reflect.Type
+			if t, rest = typeOf(rest); t == nil {
+				return nil, ""
+			}
+			fields = append(fields, reflect.StructField{
+				Name: "F" + strconv.Itoa(len(fields)),
+				Type: t,
+			})
+		}
+		return reflect.StructOf(fields), rest[1:]
+	}
+
+	basic := map[byte]any{
+		'y': byte(0),
+		'b': false,
+		'n': int16(0),
+		'q': uint16(0),
+		'i': int32(0),
+		'u': uint32(0),
+		'x': int64(0),
+		't': uint64(0),
+		'd': float64(0),
+		's': "",
+		'o': dbus.ObjectPath(""),
+		'g': dbus.Signature{},
+		'h': dbus.UnixFDIndex(0),
+		'v': dbus.Variant{},
+	}
+	v, ok := basic[sig[0]]
+	if !ok {
+		return nil, ""
+	}
+	return reflect.TypeOf(v), sig[1:]
+}
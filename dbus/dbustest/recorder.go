@@ -0,0 +1,212 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+)
+
+//go:generate go run gen_recorder.go
+
+// Call is a method call recorded by a [Recorder].
+type Call struct {
+	Method string // The name of the method, e.g. StartUnitContext
+	Args   []any  // The arguments, without the context and the job channel
+}
+
+// Recorder is an [sd.Manager] that records all method calls.
+//
+// Calls are passed on to Next. If Next is nil, the Recorder acts as a mock:
+// methods return zero values, and jobs finish with "done" right away. The
+// result of a job is sent to the job channel before the method returns, so
+// the channel must be buffered. Errors can be injected with [Recorder.Fail],
+// in both cases.
+//
+// Most methods are generated from [sd.Manager] by gen_recorder.go; run go
+// generate after changing the interfaces.
+type Recorder struct {
+	Next sd.Manager
+
+	mu      sync.Mutex
+	calls   []Call
+	errors  map[string]error
+	lastJob uint32
+}
+
+var _ sd.Manager = (*Recorder)(nil)
+
+// Calls returns the calls recorded so far.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Reset forgets the recorded calls and the injected errors.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.errors = nil
+}
+
+// Fail makes calls of method return err, without passing them on to Next. A
+// nil err removes the injected error.
+func (r *Recorder) Fail(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.errors == nil {
+		r.errors = make(map[string]error)
+	}
+	if err == nil {
+		delete(r.errors, method)
+	} else {
+		r.errors[method] = err
+	}
+}
+
+// record records a call of method and returns the error injected for it.
+func (r *Recorder) record(method string, args ...any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	return r.errors[method]
+}
+
+// nextJob returns the ID of the next job of the mock.
+func (r *Recorder) nextJob() uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastJob++
+	return r.lastJob
+}
+
+// newJob returns a job of the mock, which finished with "done" right away.
+func (r *Recorder) newJob(unit, jobType string) *mockJob {
+	id := r.nextJob()
+	return &mockJob{
+		id:      id,
+		path:    dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/systemd1/job/%d", id)),
+		unit:    unit,
+		jobType: jobType,
+	}
+}
+
+// mockJob enqueues a job of the mock and sends its result to ch.
+func (r *Recorder) mockJob(ch chan<- string) (int, error) {
+	id := r.nextJob()
+	if ch != nil {
+		select {
+		case ch <- "done":
+		default:
+			panic("dbustest: the job channel passed to the Recorder mock must be buffered")
+		}
+	}
+	return int(id), nil
+}
+
+// mockJob is a job of the mock.
+type mockJob struct {
+	id      uint32
+	path    dbus.ObjectPath
+	unit    string
+	jobType string
+}
+
+func (j *mockJob) ID() uint32                                      { return j.id }
+func (j *mockJob) Path() dbus.ObjectPath                           { return j.path }
+func (j *mockJob) Unit() string                                    { return j.unit }
+func (j *mockJob) Type() string                                    { return j.jobType }
+func (j *mockJob) Wait(ctx context.Context) error                  { return nil }
+func (j *mockJob) Result() (sd.JobResult, bool)                    { return sd.JobDone, true }
+func (j *mockJob) Cancel(ctx context.Context) error                { return nil }
+func (j *mockJob) GetAfter(ctx context.Context) ([]sd.Job, error)  { return nil, nil }
+func (j *mockJob) GetBefore(ctx context.Context) ([]sd.Job, error) { return nil, nil }
+
+// DumpByFileDescriptor returns the dump of Next. Without Next, it returns an
+// empty file.
+func (r *Recorder) DumpByFileDescriptor(ctx context.Context) (*os.File, error) {
+	if err := r.record("DumpByFileDescriptor"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.DumpByFileDescriptor(ctx)
+	}
+	return os.Open(os.DevNull)
+}
+
+// Events returns the events of Next. Without Next, the returned channel
+// receives no events and is closed when ctx is done.
+func (r *Recorder) Events(ctx context.Context) (<-chan sd.Event, error) {
+	if err := r.record("Events"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.Events(ctx)
+	}
+
+	ch := make(chan sd.Event)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// SubscribeUnitsCustomContext returns the updates of Next. Without Next, the
+// returned channels receive nothing and are closed when ctx is done. An
+// injected error is sent on the error channel.
+func (r *Recorder) SubscribeUnitsCustomContext(ctx context.Context, interval time.Duration, buffer int, isChanged func(*sd.UnitStatus, *sd.UnitStatus) bool, filterUnit func(string) bool) (<-chan map[string]*sd.UnitStatus, <-chan error) {
+	err := r.record("SubscribeUnitsCustomContext", interval, buffer)
+	if err == nil && r.Next != nil {
+		return r.Next.SubscribeUnitsCustomContext(ctx, interval, buffer, isChanged, filterUnit)
+	}
+
+	statusChan := make(chan map[string]*sd.UnitStatus, buffer)
+	errChan := make(chan error, max(buffer, 1))
+	if err != nil {
+		errChan <- err
+	}
+	go func() {
+		<-ctx.Done()
+		close(statusChan)
+		close(errChan)
+	}()
+	return statusChan, errChan
+}
+
+func (r *Recorder) Connected() bool {
+	_ = r.record("Connected")
+	if r.Next != nil {
+		return r.Next.Connected()
+	}
+	return true
+}
+
+func (r *Recorder) Close() {
+	_ = r.record("Close")
+	if r.Next != nil {
+		r.Next.Close()
+	}
+}
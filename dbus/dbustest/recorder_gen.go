@@ -0,0 +1,863 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_recorder.go; DO NOT EDIT.
+
+package dbustest
+
+import (
+	"context"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+)
+
+func (r *Recorder) StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("StartUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.StartUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("StopUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.StopUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) ReloadUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("ReloadUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("RestartUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.RestartUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) TryRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("TryRestartUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.TryRestartUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) ReloadOrRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("ReloadOrRestartUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadOrRestartUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) ReloadOrTryRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	if err := r.record("ReloadOrTryRestartUnitContext", name, mode); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadOrTryRestartUnitContext(ctx, name, mode, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) StartTransientUnitContext(ctx context.Context, name string, mode string, properties []sd.Property, ch chan<- string) (int, error) {
+	if err := r.record("StartTransientUnitContext", name, mode, properties); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.StartTransientUnitContext(ctx, name, mode, properties, ch)
+	}
+	return r.mockJob(ch)
+}
+
+func (r *Recorder) KillUnitWithTarget(ctx context.Context, name string, target sd.Who, signal int32) error {
+	if err := r.record("KillUnitWithTarget", name, target, signal); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.KillUnitWithTarget(ctx, name, target, signal)
+	}
+	return nil
+}
+
+func (r *Recorder) ResetFailedUnitContext(ctx context.Context, name string) error {
+	if err := r.record("ResetFailedUnitContext", name); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.ResetFailedUnitContext(ctx, name)
+	}
+	return nil
+}
+
+func (r *Recorder) StartUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("StartUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.StartUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "start"), nil
+}
+
+func (r *Recorder) StopUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("StopUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.StopUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "stop"), nil
+}
+
+func (r *Recorder) ReloadUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("ReloadUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "reload"), nil
+}
+
+func (r *Recorder) RestartUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("RestartUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.RestartUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "restart"), nil
+}
+
+func (r *Recorder) TryRestartUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("TryRestartUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.TryRestartUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "try-restart"), nil
+}
+
+func (r *Recorder) ReloadOrRestartUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("ReloadOrRestartUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadOrRestartUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "reload-or-restart"), nil
+}
+
+func (r *Recorder) ReloadOrTryRestartUnitJob(ctx context.Context, name string, mode string) (sd.Job, error) {
+	if err := r.record("ReloadOrTryRestartUnitJob", name, mode); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadOrTryRestartUnitJob(ctx, name, mode)
+	}
+	return r.newJob(name, "reload-or-try-restart"), nil
+}
+
+func (r *Recorder) StartTransientUnitJob(ctx context.Context, name string, mode string, properties []sd.Property, aux []sd.PropertyCollection) (sd.Job, error) {
+	if err := r.record("StartTransientUnitJob", name, mode, properties, aux); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.StartTransientUnitJob(ctx, name, mode, properties, aux)
+	}
+	return r.newJob(name, "start"), nil
+}
+
+func (r *Recorder) StartTransientTimer(ctx context.Context, name string, mode string, timer []sd.Property, service []sd.Property) (string, string, sd.Job, error) {
+	if err := r.record("StartTransientTimer", name, mode, timer, service); err != nil {
+		return "", "", nil, err
+	}
+	if r.Next != nil {
+		return r.Next.StartTransientTimer(ctx, name, mode, timer, service)
+	}
+	return "", "", r.newJob(name, "start"), nil
+}
+
+func (r *Recorder) StartTransientPathUnit(ctx context.Context, name string, mode string, path []sd.Property, service []sd.Property) (string, string, sd.Job, error) {
+	if err := r.record("StartTransientPathUnit", name, mode, path, service); err != nil {
+		return "", "", nil, err
+	}
+	if r.Next != nil {
+		return r.Next.StartTransientPathUnit(ctx, name, mode, path, service)
+	}
+	return "", "", r.newJob(name, "start"), nil
+}
+
+func (r *Recorder) EnqueueUnitJob(ctx context.Context, name, jobType, mode string) (sd.Job, []sd.Job, error) {
+	if err := r.record("EnqueueUnitJob", name, jobType, mode); err != nil {
+		return nil, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.EnqueueUnitJob(ctx, name, jobType, mode)
+	}
+	return r.newJob(name, jobType), nil, nil
+}
+
+func (r *Recorder) CleanUnit(ctx context.Context, name string, mask []string) error {
+	if err := r.record("CleanUnit", name, mask); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.CleanUnit(ctx, name, mask)
+	}
+	return nil
+}
+
+func (r *Recorder) BindMountUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool) error {
+	if err := r.record("BindMountUnit", name, source, destination, readOnly, mkdir); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.BindMountUnit(ctx, name, source, destination, readOnly, mkdir)
+	}
+	return nil
+}
+
+func (r *Recorder) MountImageUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool, options []sd.MountImageOption) error {
+	if err := r.record("MountImageUnit", name, source, destination, readOnly, mkdir, options); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.MountImageUnit(ctx, name, source, destination, readOnly, mkdir, options)
+	}
+	return nil
+}
+
+func (r *Recorder) QueueSignalUnit(ctx context.Context, name string, target sd.Who, signal int32, value int32) error {
+	if err := r.record("QueueSignalUnit", name, target, signal, value); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.QueueSignalUnit(ctx, name, target, signal, value)
+	}
+	return nil
+}
+
+func (r *Recorder) GetUnitMarkers(ctx context.Context, name string) ([]string, error) {
+	if err := r.record("GetUnitMarkers", name); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitMarkers(ctx, name)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) EnqueueMarkedJobs(ctx context.Context) ([]sd.Job, error) {
+	if err := r.record("EnqueueMarkedJobs"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.EnqueueMarkedJobs(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitsContext(ctx context.Context) ([]sd.UnitStatus, error) {
+	if err := r.record("ListUnitsContext"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitsContext(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitsFilteredContext(ctx context.Context, states []string) ([]sd.UnitStatus, error) {
+	if err := r.record("ListUnitsFilteredContext", states); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitsFilteredContext(ctx, states)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitsByPatternsContext(ctx context.Context, states []string, patterns []string) ([]sd.UnitStatus, error) {
+	if err := r.record("ListUnitsByPatternsContext", states, patterns); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitsByPatternsContext(ctx, states, patterns)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitsByNamesContext(ctx context.Context, units []string) ([]sd.UnitStatus, error) {
+	if err := r.record("ListUnitsByNamesContext", units); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitsByNamesContext(ctx, units)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetUnitPropertiesContext(ctx context.Context, unit string) (map[string]any, error) {
+	if err := r.record("GetUnitPropertiesContext", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitPropertiesContext(ctx, unit)
+	}
+	return map[string]any{}, nil
+}
+
+func (r *Recorder) GetUnitPropertyContext(ctx context.Context, unit string, propertyName string) (*sd.Property, error) {
+	if err := r.record("GetUnitPropertyContext", unit, propertyName); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitPropertyContext(ctx, unit, propertyName)
+	}
+	return &sd.Property{}, nil
+}
+
+func (r *Recorder) GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]any, error) {
+	if err := r.record("GetUnitTypePropertiesContext", unit, unitType); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitTypePropertiesContext(ctx, unit, unitType)
+	}
+	return map[string]any{}, nil
+}
+
+func (r *Recorder) SetUnitPropertiesContext(ctx context.Context, name string, runtime bool, properties ...sd.Property) error {
+	if err := r.record("SetUnitPropertiesContext", name, runtime, properties); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.SetUnitPropertiesContext(ctx, name, runtime, properties...)
+	}
+	return nil
+}
+
+func (r *Recorder) GetTypedUnitProperties(ctx context.Context, unit string) (*sd.UnitProperties, error) {
+	if err := r.record("GetTypedUnitProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedUnitProperties(ctx, unit)
+	}
+	return &sd.UnitProperties{}, nil
+}
+
+func (r *Recorder) GetTypedServiceProperties(ctx context.Context, unit string) (*sd.ServiceProperties, error) {
+	if err := r.record("GetTypedServiceProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedServiceProperties(ctx, unit)
+	}
+	return &sd.ServiceProperties{}, nil
+}
+
+func (r *Recorder) GetTypedSocketProperties(ctx context.Context, unit string) (*sd.SocketProperties, error) {
+	if err := r.record("GetTypedSocketProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedSocketProperties(ctx, unit)
+	}
+	return &sd.SocketProperties{}, nil
+}
+
+func (r *Recorder) GetTypedTimerProperties(ctx context.Context, unit string) (*sd.TimerProperties, error) {
+	if err := r.record("GetTypedTimerProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedTimerProperties(ctx, unit)
+	}
+	return &sd.TimerProperties{}, nil
+}
+
+func (r *Recorder) GetTypedMountProperties(ctx context.Context, unit string) (*sd.MountProperties, error) {
+	if err := r.record("GetTypedMountProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedMountProperties(ctx, unit)
+	}
+	return &sd.MountProperties{}, nil
+}
+
+func (r *Recorder) GetTypedSliceProperties(ctx context.Context, unit string) (*sd.SliceProperties, error) {
+	if err := r.record("GetTypedSliceProperties", unit); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetTypedSliceProperties(ctx, unit)
+	}
+	return &sd.SliceProperties{}, nil
+}
+
+func (r *Recorder) ListJobsContext(ctx context.Context) ([]sd.JobStatus, error) {
+	if err := r.record("ListJobsContext"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListJobsContext(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) CancelJob(ctx context.Context, id uint32) error {
+	if err := r.record("CancelJob", id); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.CancelJob(ctx, id)
+	}
+	return nil
+}
+
+func (r *Recorder) GetJobs(ctx context.Context) ([]sd.Job, error) {
+	if err := r.record("GetJobs"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetJobs(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetJobAfter(ctx context.Context, id uint32) ([]sd.Job, error) {
+	if err := r.record("GetJobAfter", id); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetJobAfter(ctx, id)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetJobBefore(ctx context.Context, id uint32) ([]sd.Job, error) {
+	if err := r.record("GetJobBefore", id); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetJobBefore(ctx, id)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitFilesContext(ctx context.Context) ([]sd.UnitFile, error) {
+	if err := r.record("ListUnitFilesContext"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitFilesContext(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ListUnitFilesByPatternsContext(ctx context.Context, states []string, patterns []string) ([]sd.UnitFile, error) {
+	if err := r.record("ListUnitFilesByPatternsContext", states, patterns); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ListUnitFilesByPatternsContext(ctx, states, patterns)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetUnitFileState(ctx context.Context, name string) (string, error) {
+	if err := r.record("GetUnitFileState", name); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitFileState(ctx, name)
+	}
+	return "", nil
+}
+
+func (r *Recorder) LinkUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) ([]sd.LinkUnitFileChange, error) {
+	if err := r.record("LinkUnitFilesContext", files, runtime, force); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.LinkUnitFilesContext(ctx, files, runtime, force)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) EnableUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) (bool, []sd.EnableUnitFileChange, error) {
+	if err := r.record("EnableUnitFilesContext", files, runtime, force); err != nil {
+		return false, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.EnableUnitFilesContext(ctx, files, runtime, force)
+	}
+	return false, nil, nil
+}
+
+func (r *Recorder) DisableUnitFilesContext(ctx context.Context, files []string, runtime bool) ([]sd.DisableUnitFileChange, error) {
+	if err := r.record("DisableUnitFilesContext", files, runtime); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.DisableUnitFilesContext(ctx, files, runtime)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) MaskUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) ([]sd.MaskUnitFileChange, error) {
+	if err := r.record("MaskUnitFilesContext", files, runtime, force); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.MaskUnitFilesContext(ctx, files, runtime, force)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) UnmaskUnitFilesContext(ctx context.Context, files []string, runtime bool) ([]sd.UnmaskUnitFileChange, error) {
+	if err := r.record("UnmaskUnitFilesContext", files, runtime); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.UnmaskUnitFilesContext(ctx, files, runtime)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetUnitFileLinks(ctx context.Context, name string, runtime bool) ([]string, error) {
+	if err := r.record("GetUnitFileLinks", name, runtime); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetUnitFileLinks(ctx, name, runtime)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) EnableUnitFilesWithFlags(ctx context.Context, files []string, flags sd.UnitFileFlags) (bool, []sd.EnableUnitFileChange, error) {
+	if err := r.record("EnableUnitFilesWithFlags", files, flags); err != nil {
+		return false, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.EnableUnitFilesWithFlags(ctx, files, flags)
+	}
+	return false, nil, nil
+}
+
+func (r *Recorder) ReenableUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []sd.ReenableUnitFileChange, error) {
+	if err := r.record("ReenableUnitFiles", files, runtime, force); err != nil {
+		return false, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.ReenableUnitFiles(ctx, files, runtime, force)
+	}
+	return false, nil, nil
+}
+
+func (r *Recorder) PresetUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []sd.PresetUnitFileChange, error) {
+	if err := r.record("PresetUnitFiles", files, runtime, force); err != nil {
+		return false, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.PresetUnitFiles(ctx, files, runtime, force)
+	}
+	return false, nil, nil
+}
+
+func (r *Recorder) PresetUnitFilesWithMode(ctx context.Context, files []string, mode string, runtime bool, force bool) (bool, []sd.PresetUnitFileChange, error) {
+	if err := r.record("PresetUnitFilesWithMode", files, mode, runtime, force); err != nil {
+		return false, nil, err
+	}
+	if r.Next != nil {
+		return r.Next.PresetUnitFilesWithMode(ctx, files, mode, runtime, force)
+	}
+	return false, nil, nil
+}
+
+func (r *Recorder) PresetAllUnitFiles(ctx context.Context, mode string, runtime bool, force bool) ([]sd.PresetUnitFileChange, error) {
+	if err := r.record("PresetAllUnitFiles", mode, runtime, force); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.PresetAllUnitFiles(ctx, mode, runtime, force)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) RevertUnitFiles(ctx context.Context, files []string) ([]sd.RevertUnitFileChange, error) {
+	if err := r.record("RevertUnitFiles", files); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.RevertUnitFiles(ctx, files)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) AddDependencyUnitFiles(ctx context.Context, files []string, target string, depType string, runtime bool, force bool) ([]sd.AddDependencyUnitFileChange, error) {
+	if err := r.record("AddDependencyUnitFiles", files, target, depType, runtime, force); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.AddDependencyUnitFiles(ctx, files, target, depType, runtime, force)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetDefaultTarget(ctx context.Context) (string, error) {
+	if err := r.record("GetDefaultTarget"); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.GetDefaultTarget(ctx)
+	}
+	return "", nil
+}
+
+func (r *Recorder) SetDefaultTarget(ctx context.Context, name string, force bool) ([]sd.SetDefaultTargetChange, error) {
+	if err := r.record("SetDefaultTarget", name, force); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.SetDefaultTarget(ctx, name, force)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) ReloadContext(ctx context.Context) error {
+	if err := r.record("ReloadContext"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.ReloadContext(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) Reexecute(ctx context.Context) error {
+	if err := r.record("Reexecute"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.Reexecute(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) Halt(ctx context.Context) error {
+	if err := r.record("Halt"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.Halt(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) PowerOff(ctx context.Context) error {
+	if err := r.record("PowerOff"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.PowerOff(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) Reboot(ctx context.Context) error {
+	if err := r.record("Reboot"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.Reboot(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) KExec(ctx context.Context) error {
+	if err := r.record("KExec"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.KExec(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) SoftReboot(ctx context.Context, newRoot string) error {
+	if err := r.record("SoftReboot", newRoot); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.SoftReboot(ctx, newRoot)
+	}
+	return nil
+}
+
+func (r *Recorder) SetEnvironment(ctx context.Context, assignments []string) error {
+	if err := r.record("SetEnvironment", assignments); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.SetEnvironment(ctx, assignments)
+	}
+	return nil
+}
+
+func (r *Recorder) UnsetEnvironment(ctx context.Context, names []string) error {
+	if err := r.record("UnsetEnvironment", names); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.UnsetEnvironment(ctx, names)
+	}
+	return nil
+}
+
+func (r *Recorder) UnsetAndSetEnvironment(ctx context.Context, names []string, assignments []string) error {
+	if err := r.record("UnsetAndSetEnvironment", names, assignments); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.UnsetAndSetEnvironment(ctx, names, assignments)
+	}
+	return nil
+}
+
+func (r *Recorder) GetLogLevel(ctx context.Context) (string, error) {
+	if err := r.record("GetLogLevel"); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.GetLogLevel(ctx)
+	}
+	return "", nil
+}
+
+func (r *Recorder) SetLogLevel(ctx context.Context, level string) error {
+	if err := r.record("SetLogLevel", level); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.SetLogLevel(ctx, level)
+	}
+	return nil
+}
+
+func (r *Recorder) GetLogTarget(ctx context.Context) (string, error) {
+	if err := r.record("GetLogTarget"); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.GetLogTarget(ctx)
+	}
+	return "", nil
+}
+
+func (r *Recorder) SetLogTarget(ctx context.Context, target string) error {
+	if err := r.record("SetLogTarget", target); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.SetLogTarget(ctx, target)
+	}
+	return nil
+}
+
+func (r *Recorder) ResetFailed(ctx context.Context) error {
+	if err := r.record("ResetFailed"); err != nil {
+		return err
+	}
+	if r.Next != nil {
+		return r.Next.ResetFailed(ctx)
+	}
+	return nil
+}
+
+func (r *Recorder) Dump(ctx context.Context) (string, error) {
+	if err := r.record("Dump"); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.Dump(ctx)
+	}
+	return "", nil
+}
+
+func (r *Recorder) LookupDynamicUserByName(ctx context.Context, name string) (uint32, error) {
+	if err := r.record("LookupDynamicUserByName", name); err != nil {
+		return 0, err
+	}
+	if r.Next != nil {
+		return r.Next.LookupDynamicUserByName(ctx, name)
+	}
+	return 0, nil
+}
+
+func (r *Recorder) LookupDynamicUserByUID(ctx context.Context, uid uint32) (string, error) {
+	if err := r.record("LookupDynamicUserByUID", uid); err != nil {
+		return "", err
+	}
+	if r.Next != nil {
+		return r.Next.LookupDynamicUserByUID(ctx, uid)
+	}
+	return "", nil
+}
+
+func (r *Recorder) GetDynamicUsers(ctx context.Context) ([]sd.DynamicUser, error) {
+	if err := r.record("GetDynamicUsers"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetDynamicUsers(ctx)
+	}
+	return nil, nil
+}
+
+func (r *Recorder) GetManagerProperties(ctx context.Context) (*sd.ManagerProperties, error) {
+	if err := r.record("GetManagerProperties"); err != nil {
+		return nil, err
+	}
+	if r.Next != nil {
+		return r.Next.GetManagerProperties(ctx)
+	}
+	return &sd.ManagerProperties{}, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/dbus/dbustest"
+	"github.com/godbus/dbus/v5"
+)
+
+func TestRecorderMock(t *testing.T) {
+	r := &dbustest.Recorder{}
+	ctx := context.Background()
+
+	// The result is sent before StartUnitContext returns, without a
+	// goroutine that could leak.
+	ch := make(chan string, 1)
+	id, err := r.StartUnitContext(ctx, "foo.service", "replace", ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case result := <-ch:
+		if result != "done" {
+			t.Errorf("job result %q, want done", result)
+		}
+	default:
+		t.Error("job result was not sent")
+	}
+
+	job, err := r.RestartUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil || job.Unit() != "foo.service" || job.Type() != "restart" {
+		t.Errorf("mock job %s of %s: Wait() = %v", job.Type(), job.Unit(), err)
+	}
+	if job.ID() == 0 || int(job.ID()) == id {
+		t.Errorf("mock jobs got IDs %d and %d, want distinct nonzero IDs", id, job.ID())
+	}
+	if want := dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/systemd1/job/%d", job.ID())); job.Path() != want {
+		t.Errorf("mock job path %q, want %q", job.Path(), want)
+	}
+
+	errDenied := errors.New("access denied")
+	r.Fail("StopUnitContext", errDenied)
+	if _, err := r.StopUnitContext(ctx, "foo.service", "fail", nil); err != errDenied {
+		t.Errorf("StopUnitContext() = %v, want injected error", err)
+	}
+
+	want := []dbustest.Call{
+		{Method: "StartUnitContext", Args: []any{"foo.service", "replace"}},
+		{Method: "RestartUnitJob", Args: []any{"foo.service", "replace"}},
+		{Method: "StopUnitContext", Args: []any{"foo.service", "fail"}},
+	}
+	if got := r.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %v, want %v", got, want)
+	}
+
+	r.Reset()
+	if _, err := r.StopUnitContext(ctx, "foo.service", "fail", nil); err != nil {
+		t.Errorf("StopUnitContext() = %v after Reset", err)
+	}
+	if len(r.Calls()) != 1 {
+		t.Errorf("Reset did not forget the calls")
+	}
+}
+
+func TestRecorderNext(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	r := &dbustest.Recorder{Next: conn}
+	ctx := context.Background()
+
+	ch := make(chan string, 1)
+	if _, err := r.StartUnitContext(ctx, "foo.service", "replace", ch); err != nil {
+		t.Fatal(err)
+	}
+	if result := <-ch; result != "done" {
+		t.Errorf("job result %q, want done", result)
+	}
+	if state, _ := fake.Property("foo.service", "Unit", "ActiveState"); state != "active" {
+		t.Errorf("call not passed on, ActiveState = %v", state)
+	}
+
+	units, err := r.ListUnitsContext(ctx)
+	if err != nil || len(units) != 1 {
+		t.Errorf("ListUnitsContext() = %v, %v", units, err)
+	}
+
+	job, err := r.StopUnitJob(ctx, "foo.service", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	props, err := r.GetTypedUnitProperties(ctx, "foo.service")
+	if err != nil || props.ActiveState != "inactive" {
+		t.Errorf("GetTypedUnitProperties() = %+v, %v", props, err)
+	}
+
+	if calls := r.Calls(); len(calls) != 4 || calls[1].Method != "ListUnitsContext" || calls[3].Method != "GetTypedUnitProperties" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbustest_test
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	sd "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/dbus/dbustest"
+)
+
+func TestStartInScope(t *testing.T) {
+	fake, conn := setup(t)
+	ctx := context.Background()
+
+	var out strings.Builder
+	cmd := exec.Command("/bin/echo", "hello")
+	cmd.Stdout = &out
+	scope, stop, err := conn.StartInScope(ctx, cmd, sd.ScopeOptions{
+		Name:      "echo",
+		Slice:     "test.slice",
+		MemoryMax: 1 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope != "echo.scope" {
+		t.Errorf("scope %q, want echo.scope", scope)
+	}
+	if slice, _ := fake.Property(scope, "Scope", "Slice"); slice != "test.slice" {
+		t.Errorf("scope in slice %v, want test.slice", slice)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello\n" {
+		t.Errorf("output %q, want hello", out.String())
+	}
+
+	if !slices.Contains(fake.Units(), scope) {
+		t.Fatal("scope was stopped before calling stop")
+	}
+	if err := stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(fake.Units(), scope) {
+		t.Error("scope was not collected after stopping it")
+	}
+	// Stopping a scope that is already gone is not an error.
+	if err := stop(ctx); err != nil {
+		t.Errorf("second stop() = %v", err)
+	}
+}
+
+func TestStartInScopeAttach(t *testing.T) {
+	fake, conn := setup(t, dbustest.Unit{Name: "foo.service"})
+	ctx := context.Background()
+
+	if _, _, err := conn.StartInScope(ctx, exec.Command("/bin/true"), sd.ScopeOptions{Unit: "foo.service"}); err == nil {
+		t.Error("attaching to an inactive unit succeeded")
+	}
+
+	if err := fake.SetUnitState("foo.service", "active", "running"); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("/bin/true")
+	unit, stop, err := conn.StartInScope(ctx, cmd, sd.ScopeOptions{Unit: "foo.service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit != "foo.service" {
+		t.Errorf("unit %q, want foo.service", unit)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := fake.Property("foo.service", "Unit", "ActiveState"); state != "active" {
+		t.Errorf("existing unit was stopped: %v", state)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// DropInOptions control where the drop-in methods of [Conn] find the
+// drop-ins of a unit.
+type DropInOptions struct {
+	// Root is the directory the unit directories are resolved against. If
+	// empty, / is used.
+	Root string
+
+	// Runtime selects the drop-ins in /run/systemd/system, which are lost on
+	// reboot, instead of the persistent ones in /etc/systemd/system. For
+	// connections made with [NewUserConnectionContext], the directories of
+	// the user manager are used instead: $XDG_RUNTIME_DIR/systemd/user and
+	// $XDG_CONFIG_HOME/systemd/user, which defaults to ~/.config/systemd/user.
+	Runtime bool
+}
+
+// dropInDir returns the drop-in directory of a unit.
+func (c *Conn) dropInDir(name string, opts DropInOptions) (string, error) {
+	var base string
+	switch {
+	case !c.user && opts.Runtime:
+		base = "/run/systemd/system"
+	case !c.user:
+		base = "/etc/systemd/system"
+	case opts.Runtime:
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			return "", errors.New("dbus: XDG_RUNTIME_DIR is not set")
+		}
+		base = filepath.Join(runtimeDir, "systemd/user")
+	default:
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("dbus: %w", err)
+			}
+			configDir = filepath.Join(home, ".config")
+		}
+		base = filepath.Join(configDir, "systemd/user")
+	}
+	return filepath.Join(opts.Root, base, name+".d"), nil
+}
+
+// DropIn is a drop-in of a unit.
+type DropIn struct {
+	Name     string // The file name, e.g. override.conf
+	Path     string // The full path, including DropInOptions.Root
+	Sections []*unit.UnitSection
+}
+
+// dropInFile returns the file name of the drop-in name, adding the .conf
+// suffix if it is missing.
+func dropInFile(name string) (string, error) {
+	if name == "" || strings.ContainsRune(name, '/') || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("dbus: invalid drop-in name %q", name)
+	}
+	if !strings.HasSuffix(name, ".conf") {
+		name += ".conf"
+	}
+	return name, nil
+}
+
+// WriteDropIn writes the drop-in name, e.g. "override" or "50-limits.conf",
+// for a unit and reloads the manager, as systemctl edit does. The .conf
+// suffix is added if it is missing. An existing drop-in of the same name is
+// replaced atomically. It returns the path of the drop-in.
+func (c *Conn) WriteDropIn(ctx context.Context, name, dropIn string, sections []*unit.UnitSection, opts DropInOptions) (string, error) {
+	if err := checkUnitName(name); err != nil {
+		return "", err
+	}
+	file, err := dropInFile(dropIn)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := c.dropInDir(name, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, file)
+	if err := writeFileAtomic(p, unit.SerializeSections(sections)); err != nil {
+		return "", err
+	}
+	return p, c.ReloadContext(ctx)
+}
+
+// writeFileAtomic writes the file p by renaming a temporary file over it, so
+// that systemd never reads a partially written file.
+func writeFileAtomic(p string, r io.Reader) error {
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// ListDropIns returns the drop-ins of a unit, sorted by name. Only the
+// drop-ins of the unit itself are returned, not those that apply to all
+// units of its type or slice.
+func (c *Conn) ListDropIns(name string, opts DropInOptions) ([]DropIn, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, err
+	}
+
+	dir, err := c.dropInDir(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	// os.ReadDir returns the entries sorted by file name.
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dropIns []DropIn
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".conf") {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		sections, err := readSections(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		dropIns = append(dropIns, DropIn{Name: e.Name(), Path: p, Sections: sections})
+	}
+	return dropIns, nil
+}
+
+func readSections(p string) ([]*unit.UnitSection, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return unit.DeserializeSections(f)
+}
+
+// RemoveDropIn removes the drop-in name of a unit and reloads the manager.
+// The drop-in directory is removed as well once it is empty. Removing a
+// drop-in that does not exist is not an error.
+func (c *Conn) RemoveDropIn(ctx context.Context, name, dropIn string, opts DropInOptions) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
+	file, err := dropInFile(dropIn)
+	if err != nil {
+		return err
+	}
+
+	dir, err := c.dropInDir(name, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	_ = os.Remove(dir) // Fails if other drop-ins are left
+	return c.ReloadContext(ctx)
+}
+
+// RevertDropIns removes all drop-ins of a unit, both persistent and runtime
+// ones, and reloads the manager, as systemctl revert does for drop-ins.
+// opts.Runtime is ignored. It returns the paths of the removed drop-ins.
+func (c *Conn) RevertDropIns(ctx context.Context, name string, opts DropInOptions) ([]string, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, runtime := range []bool{false, true} {
+		opts.Runtime = runtime
+		dir, err := c.dropInDir(name, opts)
+		if err != nil {
+			return removed, err
+		}
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".conf") {
+				continue
+			}
+			p := filepath.Join(dir, e.Name())
+			if err := os.Remove(p); err != nil {
+				return removed, err
+			}
+			removed = append(removed, p)
+		}
+		_ = os.Remove(dir)
+	}
+	return removed, c.ReloadContext(ctx)
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDropInDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/user/.config")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+
+	for _, tt := range []struct {
+		user bool
+		opts DropInOptions
+		want string
+	}{
+		{false, DropInOptions{}, "/etc/systemd/system/foo.service.d"},
+		{false, DropInOptions{Runtime: true}, "/run/systemd/system/foo.service.d"},
+		{false, DropInOptions{Root: "/tmp/root"}, "/tmp/root/etc/systemd/system/foo.service.d"},
+		{true, DropInOptions{}, "/home/user/.config/systemd/user/foo.service.d"},
+		{true, DropInOptions{Runtime: true}, "/run/user/1000/systemd/user/foo.service.d"},
+		{true, DropInOptions{Root: "/tmp/root", Runtime: true}, "/tmp/root/run/user/1000/systemd/user/foo.service.d"},
+	} {
+		c := &Conn{user: tt.user}
+		dir, err := c.dropInDir("foo.service", tt.opts)
+		if err != nil || dir != filepath.FromSlash(tt.want) {
+			t.Errorf("user %t, %+v: got %s (%v), want %s", tt.user, tt.opts, dir, err, tt.want)
+		}
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	c := &Conn{user: true}
+	if _, err := c.dropInDir("foo.service", DropInOptions{Runtime: true}); err == nil {
+		t.Error("no error for runtime drop-ins of a user manager without XDG_RUNTIME_DIR")
+	}
+}
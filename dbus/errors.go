@@ -0,0 +1,60 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"errors"
+
+	"github.com/coreos/go-systemd/v22/internal/dbuserr"
+	"github.com/coreos/go-systemd/v22/internal/polkit"
+)
+
+// ServiceError is returned for the D-Bus errors of systemd, logind and
+// machined that have a sentinel error in the dbus, login1 or machine1
+// packages, e.g. [ErrNoSuchUnit]. Use errors.Is with the sentinel errors to
+// check for them, or errors.As for the D-Bus error name and the message.
+// The underlying dbus.Error is still available with errors.As.
+type ServiceError = dbuserr.Error
+
+// Sentinel errors for the D-Bus errors of the systemd manager.
+var (
+	ErrNoSuchUnit               = errors.New("dbus: no such unit")
+	ErrUnitMasked               = errors.New("dbus: unit is masked")
+	ErrJobTypeNotApplicable     = errors.New("dbus: job type not applicable")
+	ErrTransactionIsDestructive = errors.New("dbus: transaction is destructive")
+	ErrOnlyByDependency         = errors.New("dbus: unit may be activated by dependency only")
+	ErrNoSuchJob                = errors.New("dbus: no such job")
+	ErrUnitGenerated            = errors.New("dbus: unit is generated")
+	ErrUnitLinked               = errors.New("dbus: unit file is linked")
+)
+
+// systemdErrors maps the D-Bus error names of the systemd manager to their
+// sentinel errors.
+var systemdErrors = map[string]error{
+	"org.freedesktop.systemd1.NoSuchUnit":               ErrNoSuchUnit,
+	"org.freedesktop.systemd1.UnitMasked":               ErrUnitMasked,
+	"org.freedesktop.systemd1.JobTypeNotApplicable":     ErrJobTypeNotApplicable,
+	"org.freedesktop.systemd1.TransactionIsDestructive": ErrTransactionIsDestructive,
+	"org.freedesktop.systemd1.OnlyByDependency":         ErrOnlyByDependency,
+	"org.freedesktop.systemd1.NoSuchJob":                ErrNoSuchJob,
+	"org.freedesktop.systemd1.UnitGenerated":            ErrUnitGenerated,
+	"org.freedesktop.systemd1.UnitLinked":               ErrUnitLinked,
+}
+
+// wrapError turns the D-Bus errors of a call into [AuthorizationError] and
+// [ServiceError] values.
+func wrapError(err error) error {
+	return dbuserr.Wrap(polkit.Wrap(err), systemdErrors)
+}
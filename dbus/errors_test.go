@@ -0,0 +1,58 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestWrapError(t *testing.T) {
+	for name, sentinel := range systemdErrors {
+		err := wrapError(dbus.Error{Name: name, Body: []any{"Unit foo.service failed."}})
+		if !errors.Is(err, sentinel) {
+			t.Errorf("%s: errors.Is(%v, %v) = false", name, err, sentinel)
+		}
+		var svcErr *ServiceError
+		if !errors.As(err, &svcErr) || svcErr.Name != name || svcErr.Error() != "Unit foo.service failed." {
+			t.Errorf("%s: got %#v", name, err)
+		}
+		var dbusErr dbus.Error
+		if !errors.As(err, &dbusErr) || dbusErr.Name != name {
+			t.Errorf("%s: the dbus.Error is not wrapped: %v", name, err)
+		}
+		for _, other := range systemdErrors {
+			if other != sentinel && errors.Is(err, other) {
+				t.Errorf("%s: errors.Is(%v, %v) = true", name, err, other)
+			}
+		}
+	}
+
+	err := wrapError(fmt.Errorf("calling: %w", dbus.NewError("org.freedesktop.DBus.Error.InteractiveAuthorizationRequired", nil)))
+	if !errors.Is(err, ErrInteractiveAuthorizationRequired) || errors.Is(err, ErrNoSuchUnit) {
+		t.Errorf("authorization error: got %v", err)
+	}
+
+	other := dbus.Error{Name: "org.freedesktop.DBus.Error.UnknownMethod"}
+	if err := wrapError(other); err.(dbus.Error).Name != other.Name {
+		t.Errorf("unknown error: got %v", err)
+	}
+	if wrapError(nil) != nil {
+		t.Error("wrapError(nil) != nil")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const managerMatch = "type='signal',interface='org.freedesktop.systemd1.Manager'"
+
+// Event is a signal emitted by the systemd manager. It is one of
+// [*UnitNewEvent], [*UnitRemovedEvent], [*JobNewEvent], [*JobRemovedEvent],
+// [*StartupFinishedEvent], [*UnitFilesChangedEvent] or [*ReloadingEvent].
+type Event interface {
+	event()
+}
+
+// UnitNewEvent is sent when a unit is loaded into memory.
+type UnitNewEvent struct {
+	Unit string          // The unit name
+	Path dbus.ObjectPath // The unit object path
+}
+
+// UnitRemovedEvent is sent when a unit is unloaded from memory.
+type UnitRemovedEvent struct {
+	Unit string          // The unit name
+	Path dbus.ObjectPath // The unit object path
+}
+
+// JobNewEvent is sent when a job is enqueued.
+type JobNewEvent struct {
+	ID   uint32          // The numeric job id
+	Job  dbus.ObjectPath // The job object path
+	Unit string          // The name of the unit the job is for
+}
+
+// JobRemovedEvent is sent when a job finished and is removed from the queue.
+type JobRemovedEvent struct {
+	ID     uint32          // The numeric job id
+	Job    dbus.ObjectPath // The job object path
+	Unit   string          // The name of the unit the job was for
+	Result JobResult       // The result of the job
+}
+
+// StartupFinishedEvent is sent when startup finished, with the time spent in
+// each phase. See [BootTimes] for the meaning of the phases.
+type StartupFinishedEvent struct {
+	Firmware  time.Duration
+	Loader    time.Duration
+	Kernel    time.Duration
+	InitRD    time.Duration
+	Userspace time.Duration
+	Total     time.Duration
+}
+
+// UnitFilesChangedEvent is sent when unit files were enabled, disabled,
+// masked or otherwise changed on disk.
+type UnitFilesChangedEvent struct{}
+
+// ReloadingEvent is sent before (Active is true) and after (Active is false)
+// the manager reloads its configuration, e.g. on systemctl daemon-reload.
+type ReloadingEvent struct {
+	Active bool
+}
+
+func (*UnitNewEvent) event()          {}
+func (*UnitRemovedEvent) event()      {}
+func (*JobNewEvent) event()           {}
+func (*JobRemovedEvent) event()       {}
+func (*StartupFinishedEvent) event()  {}
+func (*UnitFilesChangedEvent) event() {}
+func (*ReloadingEvent) event()        {}
+
+// parseEvent converts a manager signal into an Event. It returns nil for
+// other signals and for signals with an unexpected body.
+func parseEvent(signal *dbus.Signal) Event {
+	var (
+		ev   Event
+		args []any
+	)
+	switch signal.Name {
+	case "org.freedesktop.systemd1.Manager.UnitNew":
+		e := &UnitNewEvent{}
+		ev, args = e, []any{&e.Unit, &e.Path}
+	case "org.freedesktop.systemd1.Manager.UnitRemoved":
+		e := &UnitRemovedEvent{}
+		ev, args = e, []any{&e.Unit, &e.Path}
+	case "org.freedesktop.systemd1.Manager.JobNew":
+		e := &JobNewEvent{}
+		ev, args = e, []any{&e.ID, &e.Job, &e.Unit}
+	case "org.freedesktop.systemd1.Manager.JobRemoved":
+		e := &JobRemovedEvent{}
+		ev, args = e, []any{&e.ID, &e.Job, &e.Unit, &e.Result}
+	case "org.freedesktop.systemd1.Manager.StartupFinished":
+		var usec [6]uint64
+		if dbus.Store(signal.Body, &usec[0], &usec[1], &usec[2], &usec[3], &usec[4], &usec[5]) != nil {
+			return nil
+		}
+		return &StartupFinishedEvent{
+			Firmware:  usecToDuration(usec[0]),
+			Loader:    usecToDuration(usec[1]),
+			Kernel:    usecToDuration(usec[2]),
+			InitRD:    usecToDuration(usec[3]),
+			Userspace: usecToDuration(usec[4]),
+			Total:     usecToDuration(usec[5]),
+		}
+	case "org.freedesktop.systemd1.Manager.UnitFilesChanged":
+		return &UnitFilesChangedEvent{}
+	case "org.freedesktop.systemd1.Manager.Reloading":
+		e := &ReloadingEvent{}
+		ev, args = e, []any{&e.Active}
+	default:
+		return nil
+	}
+
+	if dbus.Store(signal.Body, args...) != nil {
+		return nil
+	}
+	return ev
+}
+
+// Events returns a channel receiving all signals of the systemd manager as
+// typed events. The channel is closed when ctx is done.
+//
+// Events are delivered in the order systemd sent them. Signal processing for
+// the connection never waits for the receiver: events are queued until they
+// are received, so the caller should receive promptly to bound the memory
+// used by the queue.
+func (c *Conn) Events(ctx context.Context) (<-chan Event, error) {
+	c.addMatch(ctx, managerMatch)
+	if err := c.subscribeSignals(ctx); err != nil {
+		c.removeMatch(managerMatch)
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		queue []Event
+	)
+	wake := make(chan struct{}, 1)
+	remove := c.addSignalListener(func(signal *dbus.Signal) {
+		ev := parseEvent(signal)
+		if ev == nil {
+			return
+		}
+		mu.Lock()
+		queue = append(queue, ev)
+		mu.Unlock()
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	})
+
+	ch := make(chan Event)
+	go func() {
+		defer func() {
+			remove()
+			c.removeMatch(managerMatch)
+			close(ch)
+		}()
+
+		for {
+			mu.Lock()
+			pending := queue
+			queue = nil
+			mu.Unlock()
+
+			for _, ev := range pending {
+				select {
+				case ch <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
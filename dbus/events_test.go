@@ -0,0 +1,94 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestParseEvent(t *testing.T) {
+	job := dbus.ObjectPath("/org/freedesktop/systemd1/job/3")
+	tests := []struct {
+		signal *dbus.Signal
+		want   Event
+	}{
+		{managerSignal("UnitNew", "a.service", unitPath("a.service")), &UnitNewEvent{"a.service", unitPath("a.service")}},
+		{managerSignal("UnitRemoved", "a.service", unitPath("a.service")), &UnitRemovedEvent{"a.service", unitPath("a.service")}},
+		{managerSignal("JobNew", uint32(3), job, "a.service"), &JobNewEvent{3, job, "a.service"}},
+		{managerSignal("JobRemoved", uint32(3), job, "a.service", "failed"), &JobRemovedEvent{3, job, "a.service", JobFailed}},
+		{
+			managerSignal("StartupFinished", uint64(3000000), uint64(2000000), uint64(1500000), uint64(2500000), uint64(6000000), uint64(15000000)),
+			&StartupFinishedEvent{3 * time.Second, 2 * time.Second, 1500 * time.Millisecond, 2500 * time.Millisecond, 6 * time.Second, 15 * time.Second},
+		},
+		{managerSignal("UnitFilesChanged"), &UnitFilesChangedEvent{}},
+		{managerSignal("Reloading", true), &ReloadingEvent{Active: true}},
+		{managerSignal("Reloading", "bogus"), nil},
+		{managerSignal("Unknown"), nil},
+		{propertiesChangedSignal("a.service", map[string]any{"ActiveState": "active"}), nil},
+	}
+
+	for _, tt := range tests {
+		got := parseEvent(tt.signal)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.signal.Name, got, tt.want)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	events, err := conn.Events(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		job, err := conn.StartUnitJob(t.Context(), target, "replace")
+		if err == nil {
+			_ = job.Wait(t.Context())
+		}
+		_ = conn.ReloadContext(t.Context())
+	}()
+	defer conn.StopUnitContext(t.Context(), target, "replace", nil)
+
+	var sawJob, sawReload bool
+	timeout := time.After(30 * time.Second)
+	for !sawJob || !sawReload {
+		select {
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *JobRemovedEvent:
+				if ev.Unit == target && ev.Result == JobDone {
+					sawJob = true
+				}
+			case *ReloadingEvent:
+				if !ev.Active {
+					sawReload = true
+				}
+			}
+		case <-timeout:
+			t.Fatalf("missing events: job %t, reload %t", sawJob, sawReload)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DependencyType is a kind of dependency between units, named after the
+// unit property that lists it.
+type DependencyType string
+
+const (
+	DependencyRequires   DependencyType = "Requires"
+	DependencyRequisite  DependencyType = "Requisite"
+	DependencyWants      DependencyType = "Wants"
+	DependencyBindsTo    DependencyType = "BindsTo"
+	DependencyPartOf     DependencyType = "PartOf"
+	DependencyConsistsOf DependencyType = "ConsistsOf"
+	DependencyConflicts  DependencyType = "Conflicts"
+	DependencyAfter      DependencyType = "After"
+	DependencyBefore     DependencyType = "Before"
+)
+
+// DefaultDependencyTypes are the requirement dependencies followed by
+// systemctl list-dependencies.
+var DefaultDependencyTypes = []DependencyType{
+	DependencyRequires,
+	DependencyRequisite,
+	DependencyWants,
+	DependencyConsistsOf,
+	DependencyBindsTo,
+}
+
+// isOrdering returns whether the dependency only orders units, instead of
+// pulling them in.
+func (t DependencyType) isOrdering() bool {
+	return t == DependencyAfter || t == DependencyBefore
+}
+
+// dependencies returns the units p depends on with dependency type t, or,
+// if reverse is true, the units that depend on p with type t.
+func dependencies(p *UnitProperties, t DependencyType, reverse bool) ([]string, error) {
+	type pair struct{ forward, reverse []string }
+	var deps pair
+	switch t {
+	case DependencyRequires:
+		deps = pair{p.Requires, p.RequiredBy}
+	case DependencyRequisite:
+		deps = pair{p.Requisite, p.RequisiteOf}
+	case DependencyWants:
+		deps = pair{p.Wants, p.WantedBy}
+	case DependencyBindsTo:
+		deps = pair{p.BindsTo, p.BoundBy}
+	case DependencyPartOf:
+		deps = pair{p.PartOf, p.ConsistsOf}
+	case DependencyConsistsOf:
+		deps = pair{p.ConsistsOf, p.PartOf}
+	case DependencyConflicts:
+		deps = pair{p.Conflicts, p.ConflictedBy}
+	case DependencyAfter:
+		deps = pair{p.After, p.Before}
+	case DependencyBefore:
+		deps = pair{p.Before, p.After}
+	default:
+		return nil, fmt.Errorf("dbus: unknown dependency type %q", t)
+	}
+	if reverse {
+		return deps.reverse, nil
+	}
+	return deps.forward, nil
+}
+
+// DependencyOptions control how [Conn.GetDependencyGraph] walks the unit
+// graph.
+type DependencyOptions struct {
+	// Types are the dependency types to follow. If empty,
+	// DefaultDependencyTypes is used.
+	Types []DependencyType
+
+	// Reverse follows dependencies backwards, listing the units that depend
+	// on the root unit, like systemctl list-dependencies --reverse.
+	Reverse bool
+
+	// MaxDepth limits how many levels of dependencies are followed. Zero
+	// means no limit.
+	MaxDepth int
+}
+
+// DependencyEdge is a dependency of type Type from unit From on unit To.
+// Edges always point in the direction of the dependency, also when the
+// graph was walked in reverse.
+type DependencyEdge struct {
+	From string
+	To   string
+	Type DependencyType
+}
+
+// DependencyGraph is a graph of units and the dependencies between them.
+type DependencyGraph struct {
+	Root  string           // the unit the graph was walked from
+	Units []string         // all units in the graph, in the order they were reached
+	Edges []DependencyEdge // the dependencies between the units
+}
+
+// Dependencies returns the dependencies of unit, or, if reverse is true, the
+// dependencies of other units on unit.
+func (g *DependencyGraph) Dependencies(unit string, reverse bool) []DependencyEdge {
+	var edges []DependencyEdge
+	for _, e := range g.Edges {
+		if (!reverse && e.From == unit) || (reverse && e.To == unit) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// unitLookup returns the properties of a unit.
+type unitLookup func(name string) (*UnitProperties, error)
+
+// cachedLookup returns a unitLookup fetching unit properties from systemd,
+// caching the result for repeated lookups of the same unit.
+func (c *Conn) cachedLookup(ctx context.Context) unitLookup {
+	cache := make(map[string]*UnitProperties)
+	return func(name string) (*UnitProperties, error) {
+		if p, ok := cache[name]; ok {
+			return p, nil
+		}
+		p, err := c.GetTypedUnitProperties(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		cache[name] = p
+		return p, nil
+	}
+}
+
+func buildDependencyGraph(root string, opts DependencyOptions, lookup unitLookup) (*DependencyGraph, error) {
+	types := opts.Types
+	if len(types) == 0 {
+		types = DefaultDependencyTypes
+	}
+
+	g := &DependencyGraph{Root: root, Units: []string{root}}
+	depth := map[string]int{root: 0}
+	seenEdges := make(map[DependencyEdge]bool)
+
+	// Walk breadth-first, so that units are listed at their shallowest depth.
+	for i := 0; i < len(g.Units); i++ {
+		unit := g.Units[i]
+		if opts.MaxDepth > 0 && depth[unit] >= opts.MaxDepth {
+			continue
+		}
+
+		p, err := lookup(unit)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range types {
+			deps, err := dependencies(p, t, opts.Reverse)
+			if err != nil {
+				return nil, err
+			}
+			for _, dep := range deps {
+				e := DependencyEdge{From: unit, To: dep, Type: t}
+				if opts.Reverse {
+					e.From, e.To = dep, unit
+				}
+				if !seenEdges[e] {
+					seenEdges[e] = true
+					g.Edges = append(g.Edges, e)
+				}
+				if _, ok := depth[dep]; !ok {
+					depth[dep] = depth[unit] + 1
+					g.Units = append(g.Units, dep)
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// GetDependencyGraph recursively follows the dependencies of unit, and
+// returns the graph of all units reached. This is the equivalent of
+// systemctl list-dependencies, with opts selecting the dependency types and
+// the direction.
+func (c *Conn) GetDependencyGraph(ctx context.Context, unit string, opts DependencyOptions) (*DependencyGraph, error) {
+	return buildDependencyGraph(unit, opts, c.cachedLookup(ctx))
+}
+
+// dotColors are the edge colors used by systemd-analyze dot.
+var dotColors = map[DependencyType]string{
+	DependencyRequires:   "black",
+	DependencyRequisite:  "darkblue",
+	DependencyWants:      "grey66",
+	DependencyBindsTo:    "gold",
+	DependencyPartOf:     "purple",
+	DependencyConsistsOf: "purple",
+	DependencyConflicts:  "red",
+	DependencyAfter:      "green",
+	DependencyBefore:     "green",
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language, similar to
+// systemd-analyze dot. Requirement dependencies are drawn as solid edges and
+// ordering dependencies as dashed edges, colored by dependency type.
+func (g *DependencyGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph systemd {\n")
+	for _, u := range g.Units {
+		fmt.Fprintf(&b, "\t%q;\n", u)
+	}
+	for _, e := range g.Edges {
+		style := "solid"
+		if e.Type.isOrdering() {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "\t%q->%q [color=%q, style=%s, label=%q];\n", e.From, e.To, cmp.Or(dotColors[e.Type], "black"), style, e.Type)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CriticalChainNode is a unit in the critical chain, together with the
+// units that delayed its start the most.
+type CriticalChainNode struct {
+	UnitTimes
+	Next []*CriticalChainNode
+}
+
+// String formats the critical chain like systemd-analyze critical-chain.
+func (n *CriticalChainNode) String() string {
+	var b strings.Builder
+	n.format(&b, "", "")
+	return b.String()
+}
+
+func (n *CriticalChainNode) format(b *strings.Builder, prefix, childPrefix string) {
+	b.WriteString(prefix)
+	b.WriteString(n.Unit)
+	if n.Activated > 0 {
+		b.WriteString(" @" + n.Activated.Round(time.Millisecond).String())
+	}
+	if n.Time > 0 {
+		b.WriteString(" +" + n.Time.Round(time.Millisecond).String())
+	}
+	b.WriteByte('\n')
+	for i, next := range n.Next {
+		if i == len(n.Next)-1 {
+			next.format(b, childPrefix+"└─", childPrefix+"  ")
+		} else {
+			next.format(b, childPrefix+"├─", childPrefix+"│ ")
+		}
+	}
+}
+
+func buildCriticalChain(unit string, fuzz, finish time.Duration, lookup unitLookup) (*CriticalChainNode, error) {
+	p, err := lookup(unit)
+	if err != nil {
+		return nil, err
+	}
+	root := &CriticalChainNode{UnitTimes: unitTimes(p)}
+	expanded := map[string]bool{unit: true}
+
+	var walk func(n *CriticalChainNode, p *UnitProperties) error
+	walk = func(n *CriticalChainNode, p *UnitProperties) error {
+		var candidates []*CriticalChainNode
+		var latest time.Duration
+		for _, dep := range p.After {
+			dp, err := lookup(dep)
+			if err != nil {
+				return err
+			}
+			t := unitTimes(dp)
+			// Only units activated during boot can have delayed it.
+			if t.Activated == 0 || t.Activated > finish {
+				continue
+			}
+			latest = max(latest, t.Activated)
+			candidates = append(candidates, &CriticalChainNode{UnitTimes: t})
+		}
+
+		for _, c := range candidates {
+			if latest-c.Activated <= fuzz {
+				n.Next = append(n.Next, c)
+			}
+		}
+		slices.SortStableFunc(n.Next, func(a, b *CriticalChainNode) int {
+			return cmp.Compare(b.Activated, a.Activated)
+		})
+
+		for _, next := range n.Next {
+			if expanded[next.Unit] {
+				continue
+			}
+			expanded[next.Unit] = true
+			dp, err := lookup(next.Unit)
+			if err != nil {
+				return err
+			}
+			if err := walk(next, dp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, p); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// CriticalChain returns the chain of units that delayed the activation of
+// unit the most, following After= dependencies. This is the equivalent of
+// systemd-analyze critical-chain. Dependencies activated at most fuzz before
+// the last one are included as well; with a fuzz of zero, only the units
+// activated last are followed. It returns [ErrBootNotFinished] if startup is
+// still in progress.
+func (c *Conn) CriticalChain(ctx context.Context, unit string, fuzz time.Duration) (*CriticalChainNode, error) {
+	m, err := c.GetManagerProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m.FinishTimestampMonotonic == 0 {
+		return nil, ErrBootNotFinished
+	}
+	return buildCriticalChain(unit, fuzz, m.FinishTimestampMonotonic, c.cachedLookup(ctx))
+}
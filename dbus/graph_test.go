@@ -0,0 +1,208 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testUnits is a small unit graph:
+//
+//	multi-user.target wants a.service and b.service, and is ordered after both.
+//	a.service requires c.service and is ordered after it.
+//	b.service binds to c.service.
+func testUnits() map[string]*UnitProperties {
+	s := time.Second
+	return map[string]*UnitProperties{
+		"multi-user.target": {
+			Id:                             "multi-user.target",
+			Wants:                          []string{"a.service", "b.service"},
+			After:                          []string{"a.service", "b.service"},
+			InactiveExitTimestampMonotonic: 5 * s,
+			ActiveEnterTimestampMonotonic:  5 * s,
+		},
+		"a.service": {
+			Id:                             "a.service",
+			Requires:                       []string{"c.service"},
+			WantedBy:                       []string{"multi-user.target"},
+			After:                          []string{"c.service"},
+			Before:                         []string{"multi-user.target"},
+			InactiveExitTimestampMonotonic: 3 * s,
+			ActiveEnterTimestampMonotonic:  5 * s,
+		},
+		"b.service": {
+			Id:                             "b.service",
+			BindsTo:                        []string{"c.service"},
+			WantedBy:                       []string{"multi-user.target"},
+			Before:                         []string{"multi-user.target"},
+			InactiveExitTimestampMonotonic: 1 * s,
+			ActiveEnterTimestampMonotonic:  2 * s,
+		},
+		"c.service": {
+			Id:                             "c.service",
+			RequiredBy:                     []string{"a.service"},
+			BoundBy:                        []string{"b.service"},
+			Before:                         []string{"a.service"},
+			InactiveExitTimestampMonotonic: 1 * s,
+			ActiveEnterTimestampMonotonic:  3 * s,
+		},
+	}
+}
+
+func testLookup(units map[string]*UnitProperties) unitLookup {
+	return func(name string) (*UnitProperties, error) {
+		p, ok := units[name]
+		if !ok {
+			return nil, fmt.Errorf("no such unit %s", name)
+		}
+		return p, nil
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	lookup := testLookup(testUnits())
+
+	g, err := buildDependencyGraph("multi-user.target", DependencyOptions{}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"multi-user.target", "a.service", "b.service", "c.service"}; !reflect.DeepEqual(g.Units, want) {
+		t.Fatalf("Units = %v, want %v", g.Units, want)
+	}
+	wantEdges := []DependencyEdge{
+		{"multi-user.target", "a.service", DependencyWants},
+		{"multi-user.target", "b.service", DependencyWants},
+		{"a.service", "c.service", DependencyRequires},
+		{"b.service", "c.service", DependencyBindsTo},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Fatalf("Edges = %v, want %v", g.Edges, wantEdges)
+	}
+	if deps := g.Dependencies("c.service", true); len(deps) != 2 {
+		t.Fatalf("reverse dependencies of c.service = %v", deps)
+	}
+
+	g, err = buildDependencyGraph("multi-user.target", DependencyOptions{MaxDepth: 1}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Units) != 3 {
+		t.Fatalf("Units = %v, want 3 units", g.Units)
+	}
+
+	g, err = buildDependencyGraph("c.service", DependencyOptions{Reverse: true}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEdges = []DependencyEdge{
+		{"a.service", "c.service", DependencyRequires},
+		{"b.service", "c.service", DependencyBindsTo},
+		{"multi-user.target", "a.service", DependencyWants},
+		{"multi-user.target", "b.service", DependencyWants},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Fatalf("reverse Edges = %v, want %v", g.Edges, wantEdges)
+	}
+
+	if _, err := buildDependencyGraph("a.service", DependencyOptions{Types: []DependencyType{"Bogus"}}, lookup); err == nil {
+		t.Fatal("expected error for unknown dependency type")
+	}
+}
+
+func TestDependencyGraphDOT(t *testing.T) {
+	g, err := buildDependencyGraph("a.service", DependencyOptions{
+		Types: []DependencyType{DependencyRequires, DependencyAfter},
+	}, testLookup(testUnits()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph systemd {
+	"a.service";
+	"c.service";
+	"a.service"->"c.service" [color="black", style=solid, label="Requires"];
+	"a.service"->"c.service" [color="green", style=dashed, label="After"];
+}
+`
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestCriticalChain(t *testing.T) {
+	lookup := testLookup(testUnits())
+
+	chain, err := buildCriticalChain("multi-user.target", 0, 10*time.Second, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `multi-user.target @5s
+└─a.service @5s +2s
+  └─c.service @3s +2s
+`
+	if chain.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", chain.String(), want)
+	}
+
+	chain, err = buildCriticalChain("multi-user.target", 3*time.Second, 10*time.Second, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `multi-user.target @5s
+├─a.service @5s +2s
+│ └─c.service @3s +2s
+└─b.service @2s +1s
+`
+	if chain.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", chain.String(), want)
+	}
+
+	// Units activated after boot finished are ignored.
+	chain, err = buildCriticalChain("multi-user.target", 0, 4*time.Second, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.Next) != 1 || chain.Next[0].Unit != "b.service" {
+		t.Fatalf("unexpected chain:\n%s", chain)
+	}
+}
+
+func TestGetDependencyGraph(t *testing.T) {
+	conn := setupConn(t)
+
+	g, err := conn.GetDependencyGraph(t.Context(), "multi-user.target", DependencyOptions{MaxDepth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Units) < 2 {
+		t.Fatalf("multi-user.target has no dependencies: %+v", g)
+	}
+
+	chain, err := conn.CriticalChain(t.Context(), "multi-user.target", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.Unit != "multi-user.target" {
+		t.Fatalf("unexpected chain root %q", chain.Unit)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// UnitController controls and inspects units and their jobs. It is
+// implemented by [*Conn].
+//
+// The interfaces in this file let code depend on the part of the API it
+// uses, so that it can be tested with a mock, such as the Recorder of the
+// dbustest package, or be wrapped to add retries, logging or a dry run.
+type UnitController interface {
+	StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	ReloadUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	TryRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	ReloadOrRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	ReloadOrTryRestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	StartTransientUnitContext(ctx context.Context, name string, mode string, properties []Property, ch chan<- string) (int, error)
+	KillUnitWithTarget(ctx context.Context, name string, target Who, signal int32) error
+	ResetFailedUnitContext(ctx context.Context, name string) error
+
+	StartUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	StopUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	ReloadUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	RestartUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	TryRestartUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	ReloadOrRestartUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	ReloadOrTryRestartUnitJob(ctx context.Context, name string, mode string) (Job, error)
+	StartTransientUnitJob(ctx context.Context, name string, mode string, properties []Property, aux []PropertyCollection) (Job, error)
+	StartTransientTimer(ctx context.Context, name string, mode string, timer []Property, service []Property) (string, string, Job, error)
+	StartTransientPathUnit(ctx context.Context, name string, mode string, path []Property, service []Property) (string, string, Job, error)
+	EnqueueUnitJob(ctx context.Context, name, jobType, mode string) (Job, []Job, error)
+
+	CleanUnit(ctx context.Context, name string, mask []string) error
+	BindMountUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool) error
+	MountImageUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool, options []MountImageOption) error
+	QueueSignalUnit(ctx context.Context, name string, target Who, signal int32, value int32) error
+	GetUnitMarkers(ctx context.Context, name string) ([]string, error)
+	EnqueueMarkedJobs(ctx context.Context) ([]Job, error)
+
+	ListUnitsContext(ctx context.Context) ([]UnitStatus, error)
+	ListUnitsFilteredContext(ctx context.Context, states []string) ([]UnitStatus, error)
+	ListUnitsByPatternsContext(ctx context.Context, states []string, patterns []string) ([]UnitStatus, error)
+	ListUnitsByNamesContext(ctx context.Context, units []string) ([]UnitStatus, error)
+
+	GetUnitPropertiesContext(ctx context.Context, unit string) (map[string]any, error)
+	GetUnitPropertyContext(ctx context.Context, unit string, propertyName string) (*Property, error)
+	GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]any, error)
+	SetUnitPropertiesContext(ctx context.Context, name string, runtime bool, properties ...Property) error
+
+	GetTypedUnitProperties(ctx context.Context, unit string) (*UnitProperties, error)
+	GetTypedServiceProperties(ctx context.Context, unit string) (*ServiceProperties, error)
+	GetTypedSocketProperties(ctx context.Context, unit string) (*SocketProperties, error)
+	GetTypedTimerProperties(ctx context.Context, unit string) (*TimerProperties, error)
+	GetTypedMountProperties(ctx context.Context, unit string) (*MountProperties, error)
+	GetTypedSliceProperties(ctx context.Context, unit string) (*SliceProperties, error)
+
+	ListJobsContext(ctx context.Context) ([]JobStatus, error)
+	CancelJob(ctx context.Context, id uint32) error
+	GetJobs(ctx context.Context) ([]Job, error)
+	GetJobAfter(ctx context.Context, id uint32) ([]Job, error)
+	GetJobBefore(ctx context.Context, id uint32) ([]Job, error)
+}
+
+// UnitFileManager manages unit files and their enablement. It is
+// implemented by [*Conn].
+type UnitFileManager interface {
+	ListUnitFilesContext(ctx context.Context) ([]UnitFile, error)
+	ListUnitFilesByPatternsContext(ctx context.Context, states []string, patterns []string) ([]UnitFile, error)
+	GetUnitFileState(ctx context.Context, name string) (string, error)
+	LinkUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) ([]LinkUnitFileChange, error)
+	EnableUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) (bool, []EnableUnitFileChange, error)
+	DisableUnitFilesContext(ctx context.Context, files []string, runtime bool) ([]DisableUnitFileChange, error)
+	MaskUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) ([]MaskUnitFileChange, error)
+	UnmaskUnitFilesContext(ctx context.Context, files []string, runtime bool) ([]UnmaskUnitFileChange, error)
+	GetUnitFileLinks(ctx context.Context, name string, runtime bool) ([]string, error)
+	EnableUnitFilesWithFlags(ctx context.Context, files []string, flags UnitFileFlags) (bool, []EnableUnitFileChange, error)
+	ReenableUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []ReenableUnitFileChange, error)
+	PresetUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []PresetUnitFileChange, error)
+	PresetUnitFilesWithMode(ctx context.Context, files []string, mode string, runtime bool, force bool) (bool, []PresetUnitFileChange, error)
+	PresetAllUnitFiles(ctx context.Context, mode string, runtime bool, force bool) ([]PresetUnitFileChange, error)
+	RevertUnitFiles(ctx context.Context, files []string) ([]RevertUnitFileChange, error)
+	AddDependencyUnitFiles(ctx context.Context, files []string, target string, depType string, runtime bool, force bool) ([]AddDependencyUnitFileChange, error)
+	GetDefaultTarget(ctx context.Context) (string, error)
+	SetDefaultTarget(ctx context.Context, name string, force bool) ([]SetDefaultTargetChange, error)
+	ReloadContext(ctx context.Context) error
+}
+
+// SystemController controls the lifecycle, environment and logging of the
+// systemd manager itself. It is implemented by [*Conn].
+type SystemController interface {
+	Reexecute(ctx context.Context) error
+	Halt(ctx context.Context) error
+	PowerOff(ctx context.Context) error
+	Reboot(ctx context.Context) error
+	KExec(ctx context.Context) error
+	SoftReboot(ctx context.Context, newRoot string) error
+
+	SetEnvironment(ctx context.Context, assignments []string) error
+	UnsetEnvironment(ctx context.Context, names []string) error
+	UnsetAndSetEnvironment(ctx context.Context, names []string, assignments []string) error
+
+	GetLogLevel(ctx context.Context) (string, error)
+	SetLogLevel(ctx context.Context, level string) error
+	GetLogTarget(ctx context.Context) (string, error)
+	SetLogTarget(ctx context.Context, target string) error
+
+	ResetFailed(ctx context.Context) error
+	Dump(ctx context.Context) (string, error)
+	DumpByFileDescriptor(ctx context.Context) (*os.File, error)
+	LookupDynamicUserByName(ctx context.Context, name string) (uint32, error)
+	LookupDynamicUserByUID(ctx context.Context, uid uint32) (string, error)
+	GetDynamicUsers(ctx context.Context) ([]DynamicUser, error)
+	GetManagerProperties(ctx context.Context) (*ManagerProperties, error)
+}
+
+// UnitWatcher receives changes of units. It is implemented by [*Conn].
+type UnitWatcher interface {
+	Events(ctx context.Context) (<-chan Event, error)
+	SubscribeUnitsCustomContext(ctx context.Context, interval time.Duration, buffer int, isChanged func(*UnitStatus, *UnitStatus) bool, filterUnit func(string) bool) (<-chan map[string]*UnitStatus, <-chan error)
+}
+
+// Manager combines [UnitController], [UnitFileManager], [SystemController]
+// and [UnitWatcher] with the connection state. It is implemented by [*Conn].
+type Manager interface {
+	UnitController
+	UnitFileManager
+	SystemController
+	UnitWatcher
+
+	Connected() bool
+	Close()
+}
+
+var _ Manager = (*Conn)(nil)
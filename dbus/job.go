@@ -0,0 +1,300 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// JobResult is the result of a finished job, as reported by systemd.
+// Every result except [JobDone] is also an error, so that it can be returned
+// from [Job.Wait] and checked with errors.Is.
+type JobResult string
+
+const (
+	// JobDone indicates successful execution of a job.
+	JobDone JobResult = "done"
+	// JobCanceled indicates that a job has been canceled before it finished
+	// execution.
+	JobCanceled JobResult = "canceled"
+	// JobTimeout indicates that the job timeout was reached.
+	JobTimeout JobResult = "timeout"
+	// JobFailed indicates that the job failed.
+	JobFailed JobResult = "failed"
+	// JobDependency indicates that a job this job has been depending on
+	// failed and the job hence has been removed too.
+	JobDependency JobResult = "dependency"
+	// JobSkipped indicates that a job was skipped because it didn't apply
+	// to the unit's current state.
+	JobSkipped JobResult = "skipped"
+	// JobLost indicates that the job finished while the connection was
+	// lost, so its result is unknown. It is not reported by systemd, but
+	// when reconnecting, see [Conn.EnableReconnect].
+	JobLost JobResult = "lost"
+)
+
+func (r JobResult) Error() string {
+	return "job " + string(r)
+}
+
+// Job is a handle to a job queued in systemd. It is returned by the methods
+// that enqueue jobs, such as [Conn.StartUnitJob], and by [Conn.GetJobs].
+type Job interface {
+	// ID returns the numeric job id.
+	ID() uint32
+	// Path returns the job object path.
+	Path() dbus.ObjectPath
+	// Unit returns the name of the unit the job was enqueued for.
+	Unit() string
+	// Type returns the job type, e.g. start, stop or restart. For jobs
+	// enqueued through this package this is the type that was requested.
+	Type() string
+
+	// Wait blocks until the job is finished or ctx is done. It returns nil
+	// if the job result is [JobDone], the [JobResult] for every other
+	// result, and the context error if ctx is done first. Wait may be
+	// called multiple times and from multiple goroutines.
+	//
+	// systemd only reports the completion of jobs enqueued by other
+	// clients to subscribed connections, so [Conn.Subscribe] must be
+	// called before listing such jobs with [Conn.GetJobs] and friends to
+	// wait for them.
+	Wait(ctx context.Context) error
+	// Result returns the result of the job and whether it is known yet,
+	// i.e. whether the job finished.
+	Result() (JobResult, bool)
+	// Cancel cancels the job. Waiters of the job see [JobCanceled].
+	Cancel(ctx context.Context) error
+
+	// GetAfter returns the jobs that are waiting for this job to complete
+	// before they can run.
+	GetAfter(ctx context.Context) ([]Job, error)
+	// GetBefore returns the jobs this job is waiting for to complete before
+	// it can run.
+	GetBefore(ctx context.Context) ([]Job, error)
+}
+
+// connJob is the [Job] implementation of [Conn].
+type connJob struct {
+	conn    *Conn
+	id      uint32
+	path    dbus.ObjectPath
+	unit    string
+	jobType string
+
+	done   chan struct{}
+	once   sync.Once
+	result JobResult
+}
+
+// newJob creates a job handle and registers it with the job listener, which
+// finishes it when systemd reports its completion. If a handle for the job
+// exists already, it is returned instead, so that listing the same job
+// repeatedly does not register it again. The caller must hold c.jobListener.
+func (c *Conn) newJob(id uint32, p dbus.ObjectPath, unit, jobType string) *connJob {
+	if j, ok := c.jobListener.handles[p]; ok {
+		return j
+	}
+
+	if id == 0 {
+		// ignore error since 0 is fine if conversion fails
+		n, _ := strconv.ParseUint(path.Base(string(p)), 10, 32)
+		id = uint32(n)
+	}
+
+	j := &connJob{
+		conn:    c,
+		id:      id,
+		path:    p,
+		unit:    unit,
+		jobType: jobType,
+		done:    make(chan struct{}),
+	}
+	c.jobListener.handles[p] = j
+
+	return j
+}
+
+// enqueueJob calls a manager method that queues a single job and returns a
+// handle for it. jobType and unit describe the job as requested; systemd may
+// merge it with other jobs for the same unit.
+func (c *Conn) enqueueJob(ctx context.Context, jobType, unit, method string, args ...any) (Job, error) {
+	if err := checkUnitName(unit); err != nil {
+		return nil, err
+	}
+
+	c.jobListener.Lock()
+	defer c.jobListener.Unlock()
+
+	var p dbus.ObjectPath
+	err := c.sysobj.CallWithContext(ctx, method, 0, args...).Store(&p)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newJob(0, p, unit, jobType), nil
+}
+
+// jobsFromStatus lists jobs by calling method on obj and returns handles for
+// them. The job listener is held across the call, so that jobs finishing right
+// after being listed are still reported to [Job.Wait].
+func (c *Conn) jobsFromStatus(ctx context.Context, obj dbus.BusObject, method string, args ...any) ([]Job, error) {
+	c.jobListener.Lock()
+	defer c.jobListener.Unlock()
+
+	status, err := storeSlice[JobStatus](obj.CallWithContext(ctx, method, 0, args...).Store)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, len(status))
+	for i, s := range status {
+		jobs[i] = c.newJob(s.Id, s.JobPath, s.Unit, s.JobType)
+	}
+
+	return jobs, nil
+}
+
+func (j *connJob) ID() uint32 {
+	return j.id
+}
+
+func (j *connJob) Path() dbus.ObjectPath {
+	return j.path
+}
+
+func (j *connJob) Unit() string {
+	return j.unit
+}
+
+func (j *connJob) Type() string {
+	return j.jobType
+}
+
+func (j *connJob) finish(result string) {
+	j.once.Do(func() {
+		j.result = JobResult(result)
+		close(j.done)
+	})
+}
+
+func (j *connJob) Wait(ctx context.Context) error {
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if j.result == JobDone {
+		return nil
+	}
+	return j.result
+}
+
+func (j *connJob) Result() (JobResult, bool) {
+	select {
+	case <-j.done:
+		return j.result, true
+	default:
+		return "", false
+	}
+}
+
+func (j *connJob) Cancel(ctx context.Context) error {
+	obj := j.conn.object(j.path)
+	return obj.CallWithContext(ctx, "org.freedesktop.systemd1.Job.Cancel", 0).Store()
+}
+
+func (j *connJob) GetAfter(ctx context.Context) ([]Job, error) {
+	return j.conn.jobsFromStatus(ctx, j.conn.object(j.path), "org.freedesktop.systemd1.Job.GetAfter")
+}
+
+func (j *connJob) GetBefore(ctx context.Context) ([]Job, error) {
+	return j.conn.jobsFromStatus(ctx, j.conn.object(j.path), "org.freedesktop.systemd1.Job.GetBefore")
+}
+
+// GetJobs returns handles for all currently queued jobs. Unlike
+// [Conn.ListJobsContext], the returned jobs can be waited for and canceled.
+// Waiting for jobs enqueued by other clients requires calling
+// [Conn.Subscribe] first, as systemd only reports their completion to
+// subscribed connections. The same applies to the other methods listing jobs.
+func (c *Conn) GetJobs(ctx context.Context) ([]Job, error) {
+	return c.jobsFromStatus(ctx, c.sysobj, "org.freedesktop.systemd1.Manager.ListJobs")
+}
+
+// GetJobAfter returns the jobs that are waiting for the job with the given id
+// to complete before they can run.
+func (c *Conn) GetJobAfter(ctx context.Context, id uint32) ([]Job, error) {
+	return c.jobsFromStatus(ctx, c.sysobj, "org.freedesktop.systemd1.Manager.GetJobAfter", id)
+}
+
+// GetJobBefore returns the jobs the job with the given id is waiting for to
+// complete before it can run.
+func (c *Conn) GetJobBefore(ctx context.Context, id uint32) ([]Job, error) {
+	return c.jobsFromStatus(ctx, c.sysobj, "org.freedesktop.systemd1.Manager.GetJobBefore", id)
+}
+
+// StartUnitJob is like [Conn.StartUnitContext], but returns a [Job] handle
+// instead of reporting the result on a channel.
+func (c *Conn) StartUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "start", name, "org.freedesktop.systemd1.Manager.StartUnit", name, mode)
+}
+
+// StopUnitJob is like [Conn.StopUnitContext], but returns a [Job] handle.
+func (c *Conn) StopUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "stop", name, "org.freedesktop.systemd1.Manager.StopUnit", name, mode)
+}
+
+// ReloadUnitJob is like [Conn.ReloadUnitContext], but returns a [Job] handle.
+func (c *Conn) ReloadUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "reload", name, "org.freedesktop.systemd1.Manager.ReloadUnit", name, mode)
+}
+
+// RestartUnitJob is like [Conn.RestartUnitContext], but returns a [Job] handle.
+func (c *Conn) RestartUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "restart", name, "org.freedesktop.systemd1.Manager.RestartUnit", name, mode)
+}
+
+// TryRestartUnitJob is like [Conn.TryRestartUnitContext], but returns a [Job]
+// handle.
+func (c *Conn) TryRestartUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "try-restart", name, "org.freedesktop.systemd1.Manager.TryRestartUnit", name, mode)
+}
+
+// ReloadOrRestartUnitJob is like [Conn.ReloadOrRestartUnitContext], but
+// returns a [Job] handle.
+func (c *Conn) ReloadOrRestartUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "reload-or-restart", name, "org.freedesktop.systemd1.Manager.ReloadOrRestartUnit", name, mode)
+}
+
+// ReloadOrTryRestartUnitJob is like [Conn.ReloadOrTryRestartUnitContext], but
+// returns a [Job] handle.
+func (c *Conn) ReloadOrTryRestartUnitJob(ctx context.Context, name string, mode string) (Job, error) {
+	return c.enqueueJob(ctx, "reload-or-try-restart", name, "org.freedesktop.systemd1.Manager.ReloadOrTryRestartUnit", name, mode)
+}
+
+// StartTransientUnitJob is like [Conn.StartTransientUnitAux], but returns a
+// [Job] handle. aux may be nil.
+func (c *Conn) StartTransientUnitJob(ctx context.Context, name string, mode string, properties []Property, aux []PropertyCollection) (Job, error) {
+	if aux == nil {
+		aux = make([]PropertyCollection, 0)
+	}
+	return c.enqueueJob(ctx, "start", name, "org.freedesktop.systemd1.Manager.StartTransientUnit", name, mode, properties, aux)
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func newTestConn() *Conn {
+	c := &Conn{}
+	c.jobListener.jobs = make(map[dbus.ObjectPath][]chan<- string)
+	c.jobListener.handles = make(map[dbus.ObjectPath]*connJob)
+	return c
+}
+
+func jobRemovedSignal(id uint32, job dbus.ObjectPath, unit, result string) *dbus.Signal {
+	return &dbus.Signal{
+		Name: "org.freedesktop.systemd1.Manager.JobRemoved",
+		Body: []any{id, job, unit, result},
+	}
+}
+
+func TestJobWait(t *testing.T) {
+	c := newTestConn()
+
+	c.jobListener.Lock()
+	done := c.newJob(0, "/org/freedesktop/systemd1/job/42", "foo.service", "start")
+	failed := c.newJob(43, "/org/freedesktop/systemd1/job/43", "bar.service", "stop")
+	c.jobListener.Unlock()
+
+	if done.ID() != 42 {
+		t.Errorf("ID() = %d, want 42", done.ID())
+	}
+	if done.Unit() != "foo.service" || done.Type() != "start" {
+		t.Errorf("unexpected job description %s/%s", done.Unit(), done.Type())
+	}
+	if _, ok := done.Result(); ok {
+		t.Error("Result() should not be known before the job finished")
+	}
+
+	c.jobComplete(jobRemovedSignal(42, done.Path(), "foo.service", "done"))
+	c.jobComplete(jobRemovedSignal(43, failed.Path(), "bar.service", "failed"))
+
+	if len(c.jobListener.handles) != 0 {
+		t.Fatal("JobListener handles leaked")
+	}
+
+	if err := done.Wait(t.Context()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	// A second Wait returns the cached result.
+	if err := done.Wait(t.Context()); err != nil {
+		t.Fatalf("second Wait() = %v, want nil", err)
+	}
+	if r, ok := done.Result(); !ok || r != JobDone {
+		t.Fatalf("Result() = %q, %t, want done", r, ok)
+	}
+
+	err := failed.Wait(t.Context())
+	if !errors.Is(err, JobFailed) {
+		t.Fatalf("Wait() = %v, want %v", err, JobFailed)
+	}
+	var result JobResult
+	if !errors.As(err, &result) || result != JobFailed {
+		t.Fatalf("errors.As returned %q, want %q", result, JobFailed)
+	}
+}
+
+func TestJobWaitContext(t *testing.T) {
+	c := newTestConn()
+
+	c.jobListener.Lock()
+	job := c.newJob(0, "/org/freedesktop/systemd1/job/7", "foo.service", "start")
+	c.jobListener.Unlock()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if err := job.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	errCh := make(chan error, 2)
+	for range 2 {
+		go func() { errCh <- job.Wait(t.Context()) }()
+	}
+	c.jobComplete(jobRemovedSignal(7, job.Path(), "foo.service", "canceled"))
+	for range 2 {
+		if err := <-errCh; !errors.Is(err, JobCanceled) {
+			t.Fatalf("Wait() = %v, want %v", err, JobCanceled)
+		}
+	}
+}
+
+// TestJobResult checks that the result of a job is known as soon as it
+// finished, without waiting for it.
+func TestJobResult(t *testing.T) {
+	c := newTestConn()
+
+	c.jobListener.Lock()
+	job := c.newJob(0, "/org/freedesktop/systemd1/job/5", "foo.service", "start")
+	c.jobListener.Unlock()
+
+	c.jobComplete(jobRemovedSignal(5, job.Path(), "foo.service", "skipped"))
+	if r, ok := job.Result(); !ok || r != JobSkipped {
+		t.Fatalf("Result() = %q, %t, want skipped", r, ok)
+	}
+}
+
+func TestJobHandleReuse(t *testing.T) {
+	c := newTestConn()
+	p := dbus.ObjectPath("/org/freedesktop/systemd1/job/9")
+
+	c.jobListener.Lock()
+	first := c.newJob(0, p, "foo.service", "start")
+	second := c.newJob(9, p, "foo.service", "start")
+	c.jobListener.Unlock()
+
+	if first != second {
+		t.Fatal("newJob() returned a new handle for a known job")
+	}
+	if n := len(c.jobListener.handles); n != 1 {
+		t.Fatalf("%d jobs registered, want 1", n)
+	}
+
+	c.jobComplete(jobRemovedSignal(9, p, "foo.service", "done"))
+	if len(c.jobListener.handles) != 0 {
+		t.Fatal("JobListener handles leaked")
+	}
+	if err := first.Wait(t.Context()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestStartStopUnitJob(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	job, err := conn.StartUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Unit() != target || job.Type() != "start" || job.ID() == 0 {
+		t.Fatalf("unexpected job %d %s/%s", job.ID(), job.Unit(), job.Type())
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+
+	job, err = conn.StopUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	target := "cancelme.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	job, err := conn.StartUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := conn.GetJobs(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed Job
+	for _, j := range jobs {
+		if j.ID() == job.ID() {
+			listed = j
+		}
+	}
+	if listed == nil {
+		t.Fatalf("job %d not found in GetJobs", job.ID())
+	}
+	if listed.Unit() != target {
+		t.Fatalf("listed job has unit %q, want %q", listed.Unit(), target)
+	}
+
+	if err := job.Cancel(t.Context()); err != nil {
+		t.Fatal("couldn't cancel job ", err)
+	}
+
+	if err := job.Wait(t.Context()); !errors.Is(err, JobCanceled) {
+		t.Fatalf("Wait() = %v, want %v", err, JobCanceled)
+	}
+	if err := listed.Wait(t.Context()); !errors.Is(err, JobCanceled) {
+		t.Fatalf("Wait() on listed job = %v, want %v", err, JobCanceled)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Reexecute serializes the manager state, reexecutes the systemd binary and
+// deserializes the state again. This is the equivalent of systemctl
+// daemon-reexec. The connection is usually closed by systemd while
+// reexecuting, in which case an error is returned even though the reexec
+// succeeded; callers should reconnect afterwards.
+func (c *Conn) Reexecute(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Reexecute", 0).Store()
+}
+
+// Halt halts the machine immediately, without stopping units first. This is
+// the equivalent of systemctl halt --force. For an orderly shutdown, start
+// halt.target instead.
+func (c *Conn) Halt(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Halt", 0).Store()
+}
+
+// PowerOff powers off the machine immediately, without stopping units
+// first. This is the equivalent of systemctl poweroff --force. For an
+// orderly shutdown, start poweroff.target instead.
+func (c *Conn) PowerOff(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.PowerOff", 0).Store()
+}
+
+// Reboot reboots the machine immediately, without stopping units first.
+// This is the equivalent of systemctl reboot --force. For an orderly
+// reboot, start reboot.target instead.
+func (c *Conn) Reboot(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Reboot", 0).Store()
+}
+
+// KExec reboots into the previously loaded kexec kernel immediately,
+// without stopping units first. This is the equivalent of systemctl kexec
+// --force.
+func (c *Conn) KExec(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.KExec", 0).Store()
+}
+
+// SoftReboot restarts the userspace without rebooting the kernel, see
+// systemd-soft-reboot.service(8). If newRoot is not empty, the system
+// switches to that root file system. It requires systemd 254 or newer.
+func (c *Conn) SoftReboot(ctx context.Context, newRoot string) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.SoftReboot", 0, newRoot).Store()
+}
+
+// SetEnvironment sets environment variables of the manager, which are passed
+// to all processes it spawns. Each entry has the form "key=value". This is
+// the equivalent of systemctl set-environment.
+func (c *Conn) SetEnvironment(ctx context.Context, assignments []string) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.SetEnvironment", 0, assignments).Store()
+}
+
+// UnsetEnvironment removes environment variables from the manager. Entries
+// are either variable names, or "key=value" assignments which are only
+// removed if the value matches. This is the equivalent of systemctl
+// unset-environment.
+func (c *Conn) UnsetEnvironment(ctx context.Context, names []string) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.UnsetEnvironment", 0, names).Store()
+}
+
+// UnsetAndSetEnvironment combines [Conn.UnsetEnvironment] and
+// [Conn.SetEnvironment] into a single atomic operation. The variables in
+// names are removed first.
+func (c *Conn) UnsetAndSetEnvironment(ctx context.Context, names []string, assignments []string) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.UnsetAndSetEnvironment", 0, names, assignments).Store()
+}
+
+// getManagerProperty returns the value of a property on the
+// org.freedesktop.systemd1.Manager interface.
+func (c *Conn) getManagerProperty(ctx context.Context, name string) (dbus.Variant, error) {
+	var prop dbus.Variant
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.freedesktop.systemd1.Manager", name).Store(&prop)
+	return prop, err
+}
+
+// setManagerProperty sets a writable property on the
+// org.freedesktop.systemd1.Manager interface.
+func (c *Conn) setManagerProperty(ctx context.Context, name string, value any) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Set", 0, "org.freedesktop.systemd1.Manager", name, dbus.MakeVariant(value)).Store()
+}
+
+func (c *Conn) getManagerString(ctx context.Context, name string) (string, error) {
+	prop, err := c.getManagerProperty(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	var s string
+	err = dbus.Store([]any{prop.Value()}, &s)
+	return s, err
+}
+
+// GetLogLevel returns the current log level of the manager, e.g. info or
+// debug.
+func (c *Conn) GetLogLevel(ctx context.Context) (string, error) {
+	return c.getManagerString(ctx, "LogLevel")
+}
+
+// SetLogLevel changes the log level of the manager at runtime. This is the
+// equivalent of systemctl log-level.
+func (c *Conn) SetLogLevel(ctx context.Context, level string) error {
+	return c.setManagerProperty(ctx, "LogLevel", level)
+}
+
+// GetLogTarget returns the current log target of the manager, e.g. journal
+// or console.
+func (c *Conn) GetLogTarget(ctx context.Context) (string, error) {
+	return c.getManagerString(ctx, "LogTarget")
+}
+
+// SetLogTarget changes the log target of the manager at runtime. This is the
+// equivalent of systemctl log-target.
+func (c *Conn) SetLogTarget(ctx context.Context, target string) error {
+	return c.setManagerProperty(ctx, "LogTarget", target)
+}
+
+// ResetFailed resets the "failed" state of all units. This is the equivalent
+// of systemctl reset-failed without arguments.
+func (c *Conn) ResetFailed(ctx context.Context) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ResetFailed", 0).Store()
+}
+
+// Dump returns a human-readable dump of the manager state, as printed by
+// systemd-analyze dump. systemd rate-limits this call for unprivileged
+// clients.
+func (c *Conn) Dump(ctx context.Context) (string, error) {
+	var dump string
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Dump", 0).Store(&dump)
+	return dump, err
+}
+
+// DumpByFileDescriptor is like [Conn.Dump], but returns the dump in a sealed
+// memory file instead of a string, which avoids the D-Bus message size
+// limit. The connection must support passing file descriptors. The caller
+// is responsible for closing the returned file.
+func (c *Conn) DumpByFileDescriptor(ctx context.Context) (*os.File, error) {
+	var fd dbus.UnixFD
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.DumpByFileDescriptor", 0).Store(&fd)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), "systemd-dump"), nil
+}
+
+// DynamicUser is a user allocated for a unit with DynamicUser=yes.
+type DynamicUser struct {
+	UID  uint32 // The numeric user ID
+	Name string // The user name
+}
+
+// LookupDynamicUserByName returns the UID of the dynamic user name.
+func (c *Conn) LookupDynamicUserByName(ctx context.Context, name string) (uint32, error) {
+	var uid uint32
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.LookupDynamicUserByName", 0, name).Store(&uid)
+	return uid, err
+}
+
+// LookupDynamicUserByUID returns the name of the dynamic user with the given
+// UID.
+func (c *Conn) LookupDynamicUserByUID(ctx context.Context, uid uint32) (string, error) {
+	var name string
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.LookupDynamicUserByUID", 0, uid).Store(&name)
+	return name, err
+}
+
+// GetDynamicUsers returns all dynamic users currently allocated.
+func (c *Conn) GetDynamicUsers(ctx context.Context) ([]DynamicUser, error) {
+	return storeSlice[DynamicUser](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetDynamicUsers", 0).Store)
+}
+
+// ManagerProperties holds the properties of the
+// org.freedesktop.systemd1.Manager interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Properties
+//
+// Realtime timestamps are converted to time.Time and are zero if the event
+// did not happen. Monotonic timestamps are converted to the time.Duration
+// elapsed since boot, except for FirmwareTimestampMonotonic and
+// LoaderTimestampMonotonic, which count backwards from the start of the
+// kernel.
+type ManagerProperties struct {
+	Version                    string
+	Features                   string
+	Virtualization             string
+	ConfidentialVirtualization string
+	Architecture               string
+	Tainted                    string
+	SystemState                string
+	ExitCode                   uint8
+	SoftRebootsCount           uint32
+
+	FirmwareTimestamp                        time.Time
+	FirmwareTimestampMonotonic               time.Duration
+	LoaderTimestamp                          time.Time
+	LoaderTimestampMonotonic                 time.Duration
+	KernelTimestamp                          time.Time
+	KernelTimestampMonotonic                 time.Duration
+	InitRDTimestamp                          time.Time
+	InitRDTimestampMonotonic                 time.Duration
+	UserspaceTimestamp                       time.Time
+	UserspaceTimestampMonotonic              time.Duration
+	FinishTimestamp                          time.Time
+	FinishTimestampMonotonic                 time.Duration
+	ShutdownStartTimestamp                   time.Time
+	ShutdownStartTimestampMonotonic          time.Duration
+	SecurityStartTimestamp                   time.Time
+	SecurityStartTimestampMonotonic          time.Duration
+	SecurityFinishTimestamp                  time.Time
+	SecurityFinishTimestampMonotonic         time.Duration
+	GeneratorsStartTimestamp                 time.Time
+	GeneratorsStartTimestampMonotonic        time.Duration
+	GeneratorsFinishTimestamp                time.Time
+	GeneratorsFinishTimestampMonotonic       time.Duration
+	UnitsLoadStartTimestamp                  time.Time
+	UnitsLoadStartTimestampMonotonic         time.Duration
+	UnitsLoadFinishTimestamp                 time.Time
+	UnitsLoadFinishTimestampMonotonic        time.Duration
+	UnitsLoadTimestamp                       time.Time
+	UnitsLoadTimestampMonotonic              time.Duration
+	InitRDSecurityStartTimestamp             time.Time
+	InitRDSecurityStartTimestampMonotonic    time.Duration
+	InitRDSecurityFinishTimestamp            time.Time
+	InitRDSecurityFinishTimestampMonotonic   time.Duration
+	InitRDGeneratorsStartTimestamp           time.Time
+	InitRDGeneratorsStartTimestampMonotonic  time.Duration
+	InitRDGeneratorsFinishTimestamp          time.Time
+	InitRDGeneratorsFinishTimestampMonotonic time.Duration
+	InitRDUnitsLoadStartTimestamp            time.Time
+	InitRDUnitsLoadStartTimestampMonotonic   time.Duration
+	InitRDUnitsLoadFinishTimestamp           time.Time
+	InitRDUnitsLoadFinishTimestampMonotonic  time.Duration
+
+	LogLevel              string
+	LogTarget             string
+	NNames                uint32
+	NFailedUnits          uint32
+	NJobs                 uint32
+	NInstalledJobs        uint32
+	NFailedJobs           uint32
+	Progress              float64
+	Environment           []string
+	ConfirmSpawn          bool
+	ShowStatus            bool
+	UnitPath              []string
+	ControlGroup          string
+	DefaultStandardOutput string
+	DefaultStandardError  string
+	RuntimeWatchdogUSec   time.Duration
+	RebootWatchdogUSec    time.Duration
+	KExecWatchdogUSec     time.Duration
+	ServiceWatchdogs      bool
+}
+
+// GetManagerProperties returns the properties of the service manager.
+// Properties not supported by the running systemd version are left at their
+// zero value.
+func (c *Conn) GetManagerProperties(ctx context.Context) (*ManagerProperties, error) {
+	var props map[string]dbus.Variant
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, "org.freedesktop.systemd1.Manager").Store(&props)
+	if err != nil {
+		return nil, err
+	}
+
+	var m ManagerProperties
+	if err := decodeProperties(props, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetUnsetEnvironment(t *testing.T) {
+	conn := setupConn(t)
+
+	getEnv := func() []string {
+		prop, err := conn.getManagerProperty(t.Context(), "Environment")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prop.Value().([]string)
+	}
+
+	if err := conn.SetEnvironment(t.Context(), []string{"GO_SYSTEMD_TEST=1"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(getEnv(), "GO_SYSTEMD_TEST=1") {
+		t.Fatal("variable was not set")
+	}
+
+	err := conn.UnsetAndSetEnvironment(t.Context(), []string{"GO_SYSTEMD_TEST"}, []string{"GO_SYSTEMD_TEST2=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := getEnv()
+	if slices.Contains(env, "GO_SYSTEMD_TEST=1") || !slices.Contains(env, "GO_SYSTEMD_TEST2=2") {
+		t.Fatalf("unexpected environment %v", env)
+	}
+
+	if err := conn.UnsetEnvironment(t.Context(), []string{"GO_SYSTEMD_TEST2"}); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(getEnv(), "GO_SYSTEMD_TEST2=2") {
+		t.Fatal("variable was not unset")
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	conn := setupConn(t)
+
+	level, err := conn.GetLogLevel(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetLogLevel(t.Context(), "debug"); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.SetLogLevel(t.Context(), level)
+
+	got, err := conn.GetLogLevel(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "debug" {
+		t.Fatalf("log level is %q, want debug", got)
+	}
+
+	if _, err := conn.GetLogTarget(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDump(t *testing.T) {
+	conn := setupConn(t)
+
+	dump, err := conn.Dump(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump, "-> Unit ") {
+		t.Fatal("dump does not list any units")
+	}
+
+	f, err := conn.DumpByFileDescriptor(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "-> Unit ") {
+		t.Fatal("dump file does not list any units")
+	}
+}
+
+func TestDynamicUsers(t *testing.T) {
+	conn := setupConn(t)
+
+	users, err := conn.GetDynamicUsers(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		uid, err := conn.LookupDynamicUserByName(t.Context(), u.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uid != u.UID {
+			t.Fatalf("LookupDynamicUserByName(%q) = %d, want %d", u.Name, uid, u.UID)
+		}
+		name, err := conn.LookupDynamicUserByUID(t.Context(), u.UID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != u.Name {
+			t.Fatalf("LookupDynamicUserByUID(%d) = %q, want %q", u.UID, name, u.Name)
+		}
+	}
+}
+
+func TestResetFailed(t *testing.T) {
+	conn := setupConn(t)
+
+	if err := conn.ResetFailed(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -16,11 +16,11 @@ package dbus
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path"
 	"strconv"
 
+	"github.com/coreos/go-systemd/v22/unit"
 	"github.com/godbus/dbus/v5"
 )
 
@@ -44,21 +44,36 @@ func (c *Conn) jobComplete(signal *dbus.Signal) {
 
 	_ = dbus.Store(signal.Body, &id, &job, &unit, &result)
 	c.jobListener.Lock()
-	for _, out := range c.jobListener.jobs[job] {
+	c.completeJob(job, result)
+	c.jobListener.Unlock()
+}
+
+// completeJob reports the result of the job at p to the channels and the
+// handle waiting for it, and unregisters them. The caller must hold
+// c.jobListener.
+func (c *Conn) completeJob(p dbus.ObjectPath, result string) {
+	for _, out := range c.jobListener.jobs[p] {
 		out <- result
 	}
-	delete(c.jobListener.jobs, job)
-	c.jobListener.Unlock()
+	if j, ok := c.jobListener.handles[p]; ok {
+		j.finish(result)
+	}
+	delete(c.jobListener.jobs, p)
+	delete(c.jobListener.handles, p)
 }
 
-func (c *Conn) startJob(ctx context.Context, ch chan<- string, job string, args ...any) (int, error) {
+func (c *Conn) startJob(ctx context.Context, ch chan<- string, job string, name string, args ...any) (int, error) {
+	if err := checkUnitName(name); err != nil {
+		return 0, err
+	}
+
 	if ch != nil {
 		c.jobListener.Lock()
 		defer c.jobListener.Unlock()
 	}
 
 	var p dbus.ObjectPath
-	err := c.sysobj.CallWithContext(ctx, job, 0, args...).Store(&p)
+	err := c.sysobj.CallWithContext(ctx, job, 0, append([]any{name}, args...)...).Store(&p)
 	if err != nil {
 		return 0, err
 	}
@@ -112,6 +127,8 @@ func (c *Conn) StartUnit(name string, mode string, ch chan<- string) (int, error
 // should not be considered authoritative.
 //
 // If an error does occur, it will be returned to the user alongside a job ID of 0.
+//
+// See [Conn.StartUnitJob] for a variant that returns a [Job] handle instead.
 func (c *Conn) StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
 	return c.startJob(ctx, ch, "org.freedesktop.systemd1.Manager.StartUnit", name, mode)
 }
@@ -218,6 +235,9 @@ func (c *Conn) KillUnitContext(ctx context.Context, name string, signal int32) {
 // KillUnitWithTarget sends a signal to the specified unit.
 // The target argument can be one of [All], [Main], or [Control].
 func (c *Conn) KillUnitWithTarget(ctx context.Context, name string, target Who, signal int32) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.KillUnit", 0, name, string(target), signal).Store()
 }
 
@@ -228,6 +248,9 @@ func (c *Conn) ResetFailedUnit(name string) error {
 
 // ResetFailedUnitContext resets the "failed" state of a specific unit.
 func (c *Conn) ResetFailedUnitContext(ctx context.Context, name string) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ResetFailedUnit", 0, name).Store()
 }
 
@@ -242,7 +265,7 @@ func (c *Conn) SystemStateContext(ctx context.Context) (*Property, error) {
 	var err error
 	var prop dbus.Variant
 
-	obj := c.sysconn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1")
+	obj := c.object("/org/freedesktop/systemd1")
 	err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.freedesktop.systemd1.Manager", "SystemState").Store(&prop)
 	if err != nil {
 		return nil, err
@@ -260,7 +283,7 @@ func (c *Conn) getProperties(ctx context.Context, path dbus.ObjectPath, dbusInte
 		return nil, fmt.Errorf("invalid unit name: %v", path)
 	}
 
-	obj := c.sysconn.Object("org.freedesktop.systemd1", path)
+	obj := c.object(path)
 	err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, dbusInterface).Store(&props)
 	if err != nil {
 		return nil, err
@@ -282,6 +305,9 @@ func (c *Conn) GetUnitProperties(unit string) (map[string]any, error) {
 // GetUnitPropertiesContext takes the (unescaped) unit name and returns all of
 // its dbus object properties.
 func (c *Conn) GetUnitPropertiesContext(ctx context.Context, unit string) (map[string]any, error) {
+	if err := checkUnitName(unit); err != nil {
+		return nil, err
+	}
 	path := unitPath(unit)
 	return c.getProperties(ctx, path, "org.freedesktop.systemd1.Unit")
 }
@@ -305,6 +331,9 @@ func (c *Conn) GetAllProperties(unit string) (map[string]any, error) {
 // GetAllPropertiesContext takes the (unescaped) unit name and returns all of
 // its dbus object properties.
 func (c *Conn) GetAllPropertiesContext(ctx context.Context, unit string) (map[string]any, error) {
+	if err := checkUnitName(unit); err != nil {
+		return nil, err
+	}
 	path := unitPath(unit)
 	return c.getProperties(ctx, path, "")
 }
@@ -313,12 +342,13 @@ func (c *Conn) getProperty(ctx context.Context, unit string, dbusInterface strin
 	var err error
 	var prop dbus.Variant
 
-	path := unitPath(unit)
-	if !path.IsValid() {
-		return nil, errors.New("invalid unit name: " + unit)
+	if err := checkUnitName(unit); err != nil {
+		return nil, err
 	}
 
-	obj := c.sysconn.Object("org.freedesktop.systemd1", path)
+	path := unitPath(unit)
+
+	obj := c.object(path)
 	err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface, propertyName).Store(&prop)
 	if err != nil {
 		return nil, err
@@ -357,6 +387,9 @@ func (c *Conn) GetUnitTypeProperties(unit string, unitType string) (map[string]a
 // Valid values for unitType: Service, Socket, Target, Device, Mount, Automount, Snapshot, Timer, Swap, Path, Slice, Scope.
 // Returns "dbus.Error: Unknown interface" error if the unitType is not the correct type of the unit.
 func (c *Conn) GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]any, error) {
+	if err := checkUnitName(unit); err != nil {
+		return nil, err
+	}
 	path := unitPath(unit)
 	return c.getProperties(ctx, path, "org.freedesktop.systemd1."+unitType)
 }
@@ -374,6 +407,9 @@ func (c *Conn) SetUnitProperties(name string, runtime bool, properties ...Proper
 // to modify. properties are the settings to set, encoded as an array of property
 // name and value pairs.
 func (c *Conn) SetUnitPropertiesContext(ctx context.Context, name string, runtime bool, properties ...Property) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.SetUnitProperties", 0, name, runtime, properties).Store()
 }
 
@@ -583,20 +619,7 @@ func (c *Conn) EnableUnitFiles(files []string, runtime bool, force bool) (bool,
 // or unlink), the file name of the symlink and the destination of the
 // symlink.
 func (c *Conn) EnableUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) (bool, []EnableUnitFileChange, error) {
-	var carries_install_info bool
-	var result []any
-
-	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnableUnitFiles", 0, files, runtime, force).Store(&carries_install_info, &result)
-	if err != nil {
-		return false, nil, err
-	}
-
-	changes, err := convertSlice[EnableUnitFileChange](result)
-	if err != nil {
-		return false, nil, err
-	}
-
-	return carries_install_info, changes, nil
+	return storeInstallChanges[EnableUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnableUnitFiles", 0, files, runtime, force).Store)
 }
 
 type EnableUnitFileChange struct {
@@ -678,6 +701,151 @@ type UnmaskUnitFileChange struct {
 	Destination string // Destination of the symlink
 }
 
+// storeInstallChanges fetches the carries_install_info flag and the change
+// list returned by the methods that install unit files, and converts the
+// changes into a slice of the target type T.
+func storeInstallChanges[T any](f storeFunc) (bool, []T, error) {
+	var carriesInstallInfo bool
+	var result []any
+	if err := f(&carriesInstallInfo, &result); err != nil {
+		return false, nil, err
+	}
+
+	changes, err := convertSlice[T](result)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return carriesInstallInfo, changes, nil
+}
+
+// UnitFileFlags modify the behavior of [Conn.EnableUnitFilesWithFlags].
+type UnitFileFlags uint64
+
+const (
+	// UnitFileRuntime makes the change for runtime only (/run), instead of
+	// persistently (/etc).
+	UnitFileRuntime UnitFileFlags = 1 << 0
+	// UnitFileForce replaces existing symlinks pointing to other units.
+	UnitFileForce UnitFileFlags = 1 << 1
+	// UnitFilePortable adds or removes the symlinks of a portable service
+	// image (see portablectl(1)).
+	UnitFilePortable UnitFileFlags = 1 << 2
+)
+
+// Unit file preset modes for [Conn.PresetUnitFilesWithMode] and
+// [Conn.PresetAllUnitFiles].
+const (
+	PresetFull        = "full"         // apply both enable and disable presets
+	PresetEnableOnly  = "enable-only"  // only apply enable presets
+	PresetDisableOnly = "disable-only" // only apply disable presets
+)
+
+// GetUnitFileState returns the installation state of the unit file name,
+// e.g. enabled, disabled, static or masked. This is the equivalent of
+// systemctl is-enabled.
+func (c *Conn) GetUnitFileState(ctx context.Context, name string) (string, error) {
+	var state string
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetUnitFileState", 0, name).Store(&state)
+	return state, err
+}
+
+// GetUnitFileLinks returns the symlinks that enable the unit file name,
+// either for runtime only (true, /run) or persistently (false, /etc).
+func (c *Conn) GetUnitFileLinks(ctx context.Context, name string, runtime bool) ([]string, error) {
+	var links []string
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetUnitFileLinks", 0, name, runtime).Store(&links)
+	return links, err
+}
+
+// EnableUnitFilesWithFlags is like [Conn.EnableUnitFilesContext], but takes
+// a combination of [UnitFileFlags] instead of the runtime and force
+// booleans.
+func (c *Conn) EnableUnitFilesWithFlags(ctx context.Context, files []string, flags UnitFileFlags) (bool, []EnableUnitFileChange, error) {
+	return storeInstallChanges[EnableUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnableUnitFilesWithFlags", 0, files, uint64(flags)).Store)
+}
+
+// ReenableUnitFileChange is a change made by [Conn.ReenableUnitFiles]. It has
+// the same fields as [EnableUnitFileChange].
+type ReenableUnitFileChange EnableUnitFileChange
+
+// ReenableUnitFiles disables and then enables the given unit files again,
+// which resets their symlinks to what the Install section configures.
+// It takes the same arguments as [Conn.EnableUnitFilesContext] and returns
+// whether the units carry install information and the changes made.
+func (c *Conn) ReenableUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []ReenableUnitFileChange, error) {
+	return storeInstallChanges[ReenableUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ReenableUnitFiles", 0, files, runtime, force).Store)
+}
+
+// PresetUnitFileChange is a change made by [Conn.PresetUnitFiles] and the
+// other preset methods. It has the same fields as [EnableUnitFileChange].
+type PresetUnitFileChange EnableUnitFileChange
+
+// PresetUnitFiles enables or disables the given unit files according to the
+// preset policy, see systemd.preset(5). It takes the same arguments as
+// [Conn.EnableUnitFilesContext] and returns whether the units carry install
+// information and the changes made.
+func (c *Conn) PresetUnitFiles(ctx context.Context, files []string, runtime bool, force bool) (bool, []PresetUnitFileChange, error) {
+	return storeInstallChanges[PresetUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.PresetUnitFiles", 0, files, runtime, force).Store)
+}
+
+// PresetUnitFilesWithMode is like [Conn.PresetUnitFiles], but only applies
+// the presets selected by mode, one of [PresetFull], [PresetEnableOnly] or
+// [PresetDisableOnly].
+func (c *Conn) PresetUnitFilesWithMode(ctx context.Context, files []string, mode string, runtime bool, force bool) (bool, []PresetUnitFileChange, error) {
+	return storeInstallChanges[PresetUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.PresetUnitFilesWithMode", 0, files, mode, runtime, force).Store)
+}
+
+// PresetAllUnitFiles applies the preset policy to all installed unit files,
+// like systemctl preset-all. mode is one of [PresetFull], [PresetEnableOnly]
+// or [PresetDisableOnly].
+func (c *Conn) PresetAllUnitFiles(ctx context.Context, mode string, runtime bool, force bool) ([]PresetUnitFileChange, error) {
+	return storeSlice[PresetUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.PresetAllUnitFiles", 0, mode, runtime, force).Store)
+}
+
+// RevertUnitFileChange is a change made by [Conn.RevertUnitFiles]. It has the
+// same fields as [EnableUnitFileChange].
+type RevertUnitFileChange EnableUnitFileChange
+
+// RevertUnitFiles reverts the given unit files to their vendor versions, by
+// removing drop-ins, overriding unit files in /etc and /run, and unmasking
+// them. This is the equivalent of systemctl revert.
+func (c *Conn) RevertUnitFiles(ctx context.Context, files []string) ([]RevertUnitFileChange, error) {
+	return storeSlice[RevertUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.RevertUnitFiles", 0, files).Store)
+}
+
+// AddDependencyUnitFileChange is a change made by
+// [Conn.AddDependencyUnitFiles]. It has the same fields as
+// [EnableUnitFileChange].
+type AddDependencyUnitFileChange EnableUnitFileChange
+
+// AddDependencyUnitFiles adds a dependency of type depType (Wants or
+// Requires) from target to each of the given unit files, by creating
+// symlinks in the .wants/ or .requires/ directory of target. This is the
+// equivalent of systemctl add-wants and add-requires.
+func (c *Conn) AddDependencyUnitFiles(ctx context.Context, files []string, target string, depType string, runtime bool, force bool) ([]AddDependencyUnitFileChange, error) {
+	return storeSlice[AddDependencyUnitFileChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.AddDependencyUnitFiles", 0, files, target, depType, runtime, force).Store)
+}
+
+// GetDefaultTarget returns the name of the default target, which is the
+// target of the default.target symlink.
+func (c *Conn) GetDefaultTarget(ctx context.Context) (string, error) {
+	var name string
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetDefaultTarget", 0).Store(&name)
+	return name, err
+}
+
+// SetDefaultTargetChange is a change made by [Conn.SetDefaultTarget]. It has
+// the same fields as [EnableUnitFileChange].
+type SetDefaultTargetChange EnableUnitFileChange
+
+// SetDefaultTarget makes name the default target by pointing the
+// default.target symlink to it. If force is true, an existing symlink is
+// replaced even if it doesn't point to a target unit.
+func (c *Conn) SetDefaultTarget(ctx context.Context, name string, force bool) ([]SetDefaultTargetChange, error) {
+	return storeSlice[SetDefaultTargetChange](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.SetDefaultTarget", 0, name, force).Store)
+}
+
 // Deprecated: use ReloadContext instead.
 func (c *Conn) Reload() error {
 	return c.ReloadContext(context.Background())
@@ -689,6 +857,14 @@ func (c *Conn) ReloadContext(ctx context.Context) error {
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Reload", 0).Store()
 }
 
+// checkUnitName rejects unit names that systemd would reject, before they
+// are sent to it. It only checks the syntax systemd accepts on D-Bus, so
+// templates, instances and escaped names of any type are let through. The
+// error wraps [unit.ErrInvalidName].
+func checkUnitName(name string) error {
+	return unit.Name(name).Validate()
+}
+
 func unitPath(name string) dbus.ObjectPath {
 	return dbus.ObjectPath("/org/freedesktop/systemd1/unit/" + PathBusEscape(name))
 }
@@ -714,6 +890,7 @@ func (c *Conn) ListJobs() ([]JobStatus, error) {
 }
 
 // ListJobsContext returns an array with all currently queued jobs.
+// Use [Conn.GetJobs] to get [Job] handles that can be waited for.
 func (c *Conn) ListJobsContext(ctx context.Context) ([]JobStatus, error) {
 	return storeSlice[JobStatus](c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListJobs", 0).Store)
 }
@@ -726,15 +903,150 @@ func (c *Conn) CancelJob(ctx context.Context, id uint32) error {
 // FreezeUnit freezes the cgroup associated with the unit.
 // Note that FreezeUnit and [Conn.ThawUnit] are only supported on systems running with cgroup v2.
 func (c *Conn) FreezeUnit(ctx context.Context, unit string) error {
+	if err := checkUnitName(unit); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.FreezeUnit", 0, unit).Store()
 }
 
 // ThawUnit unfreezes the cgroup associated with the unit.
 func (c *Conn) ThawUnit(ctx context.Context, unit string) error {
+	if err := checkUnitName(unit); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ThawUnit", 0, unit).Store()
 }
 
 // AttachProcessesToUnit moves existing processes, identified by pids, into an existing systemd unit.
 func (c *Conn) AttachProcessesToUnit(ctx context.Context, unit, subcgroup string, pids []uint32) error {
+	if err := checkUnitName(unit); err != nil {
+		return err
+	}
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.AttachProcessesToUnit", 0, unit, subcgroup, pids).Store()
 }
+
+// CleanUnit removes the runtime, state, cache, logs and configuration
+// directories of the unit, as configured with RuntimeDirectory= and friends.
+// mask selects the kind of resources to remove: any of runtime, state,
+// cache, logs, configuration, fdstore or all. The unit must be inactive.
+// This is the equivalent of systemctl clean.
+func (c *Conn) CleanUnit(ctx context.Context, name string, mask []string) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.CleanUnit", 0, name, mask).Store()
+}
+
+// BindMountUnit bind mounts source from the host into the mount namespace of
+// the running unit at destination. If mkdir is true, the destination
+// directory is created if missing. The unit must run with its own mount
+// namespace, e.g. because of PrivateTmp= or ProtectSystem=.
+func (c *Conn) BindMountUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.BindMountUnit", 0, name, source, destination, readOnly, mkdir).Store()
+}
+
+// MountImageOption holds mount options for one partition of a disk image.
+type MountImageOption struct {
+	Partition string // The partition name, e.g. root or usr
+	Options   string // Comma separated mount options
+}
+
+// MountImageUnit mounts the disk image source into the mount namespace of
+// the running unit at destination, like [Conn.BindMountUnit].
+func (c *Conn) MountImageUnit(ctx context.Context, name, source, destination string, readOnly, mkdir bool, options []MountImageOption) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
+	if options == nil {
+		options = []MountImageOption{}
+	}
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.MountImageUnit", 0, name, source, destination, readOnly, mkdir, options).Store()
+}
+
+// QueueSignalUnit is like [Conn.KillUnitWithTarget], but queues a realtime
+// signal with an additional value, see sigqueue(3). The target argument can
+// be one of [All], [Main], or [Control].
+func (c *Conn) QueueSignalUnit(ctx context.Context, name string, target Who, signal int32, value int32) error {
+	if err := checkUnitName(name); err != nil {
+		return err
+	}
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.QueueSignalUnit", 0, name, string(target), signal, value).Store()
+}
+
+// GetUnitMarkers returns the markers of the unit, needs-restart and/or
+// needs-reload. Markers are set with [PropMarkers] and acted upon by
+// [Conn.EnqueueMarkedJobs].
+func (c *Conn) GetUnitMarkers(ctx context.Context, name string) ([]string, error) {
+	return GetProperty[[]string](ctx, c, name, "Unit", "Markers")
+}
+
+// EnqueueMarkedJobs enqueues restart or reload jobs for all units with
+// markers set, and clears the markers. This is the equivalent of
+// systemctl reload-or-restart --marked, as used to restart services after
+// package upgrades. systemd only reports the job paths, so the unit and
+// type of the returned jobs are empty.
+func (c *Conn) EnqueueMarkedJobs(ctx context.Context) ([]Job, error) {
+	c.jobListener.Lock()
+	defer c.jobListener.Unlock()
+
+	var paths []dbus.ObjectPath
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnqueueMarkedJobs", 0).Store(&paths)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, len(paths))
+	for i, p := range paths {
+		jobs[i] = c.newJob(0, p, "", "")
+	}
+	return jobs, nil
+}
+
+type enqueuedJob struct {
+	Id       uint32
+	JobPath  dbus.ObjectPath
+	Unit     string
+	UnitPath dbus.ObjectPath
+	JobType  string
+}
+
+// EnqueueUnitJob enqueues a job of type jobType (e.g. start, stop or
+// restart) for the unit, like [Conn.StartUnitJob] and friends. In addition
+// to the job itself, it returns the jobs of all units that were pulled into
+// the transaction. The requested job itself is not repeated in that list.
+func (c *Conn) EnqueueUnitJob(ctx context.Context, name, jobType, mode string) (Job, []Job, error) {
+	if err := checkUnitName(name); err != nil {
+		return nil, nil, err
+	}
+
+	c.jobListener.Lock()
+	defer c.jobListener.Unlock()
+
+	var (
+		main     enqueuedJob
+		affected []any
+	)
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnqueueUnitJob", 0, name, jobType, mode).
+		Store(&main.Id, &main.JobPath, &main.Unit, &main.UnitPath, &main.JobType, &affected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, err := convertSlice[enqueuedJob](affected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job := c.newJob(main.Id, main.JobPath, main.Unit, main.JobType)
+	jobs := make([]Job, 0, len(status))
+	for _, s := range status {
+		if s.JobPath == main.JobPath {
+			continue
+		}
+		jobs = append(jobs, c.newJob(s.Id, s.JobPath, s.Unit, s.JobType))
+	}
+	return job, jobs, nil
+}
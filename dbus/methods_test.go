@@ -15,6 +15,7 @@
 package dbus
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -22,10 +23,12 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/coreos/go-systemd/v22/unit"
 	"github.com/godbus/dbus/v5"
 )
 
@@ -822,6 +825,28 @@ func TestGetUnitPropertiesRejectsInvalidName(t *testing.T) {
 	}
 }
 
+// TestRejectInvalidUnitName checks that malformed unit names are rejected
+// before anything is sent; the test connection has no bus to send to.
+func TestRejectInvalidUnitName(t *testing.T) {
+	conn := newTestConn()
+	ctx := context.Background()
+
+	for _, name := range []string{"", "foo", "foo bar.service", "@foo.service"} {
+		if _, err := conn.StartUnitContext(ctx, name, "replace", nil); !errors.Is(err, unit.ErrInvalidName) {
+			t.Errorf("StartUnitContext(%q) = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := conn.StartUnitJob(ctx, name, "replace"); !errors.Is(err, unit.ErrInvalidName) {
+			t.Errorf("StartUnitJob(%q) = %v, want ErrInvalidName", name, err)
+		}
+		if err := conn.ResetFailedUnitContext(ctx, name); !errors.Is(err, unit.ErrInvalidName) {
+			t.Errorf("ResetFailedUnitContext(%q) = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := conn.GetUnitPropertiesContext(ctx, name); !errors.Is(err, unit.ErrInvalidName) {
+			t.Errorf("GetUnitPropertiesContext(%q) = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
 // TestGetServiceProperty reads the `systemd-udevd.service` which should exist
 // on all systemd systems and ensures that one of its property is valid.
 func TestGetServiceProperty(t *testing.T) {
@@ -1678,6 +1703,99 @@ func TestMaskUnmask(t *testing.T) {
 	}
 }
 
+func TestStoreInstallChanges(t *testing.T) {
+	store := func(retvalues ...any) error {
+		return dbus.Store([]any{true, []any{[]any{"symlink", "/run/a", "/b"}}}, retvalues...)
+	}
+	install, changes, err := storeInstallChanges[ReenableUnitFileChange](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ReenableUnitFileChange{{Type: "symlink", Filename: "/run/a", Destination: "/b"}}
+	if !install || !reflect.DeepEqual(changes, want) {
+		t.Fatalf("got %t, %+v, want true, %+v", install, changes, want)
+	}
+}
+
+// Enables a unit with flags, inspects and reenables it, then reverts it
+func TestUnitFileManagement(t *testing.T) {
+	target := "enable-flags.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	abs := findFixture(target, t)
+	runPath := filepath.Join("/run/systemd/system/", target)
+
+	_, changes, err := conn.EnableUnitFilesWithFlags(t.Context(), []string{abs}, UnitFileRuntime|UnitFileForce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) < 1 || changes[0].Filename != runPath {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	state, err := conn.GetUnitFileState(t.Context(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "linked-runtime" && state != "enabled-runtime" {
+		t.Fatalf("unexpected unit file state %q", state)
+	}
+
+	links, err := conn.GetUnitFileLinks(t.Context(), target, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) == 0 {
+		t.Fatal("expected at least one link")
+	}
+
+	if _, _, err := conn.ReenableUnitFiles(t.Context(), []string{target}, true, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.RevertUnitFiles(t.Context(), []string{target}); err != nil {
+		t.Fatal(err)
+	}
+	state, err = conn.GetUnitFileState(t.Context(), target)
+	if err == nil && strings.HasSuffix(state, "-runtime") {
+		t.Fatalf("unit file state after revert is %q", state)
+	}
+}
+
+func TestAddDependencyUnitFiles(t *testing.T) {
+	target := "add-dependency.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	changes, err := conn.AddDependencyUnitFiles(t.Context(), []string{target}, "multi-user.target", "Wants", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPath := filepath.Join("/run/systemd/system/multi-user.target.wants", target)
+	if len(changes) != 1 || changes[0].Filename != wantPath {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+
+	if _, err := conn.DisableUnitFilesContext(t.Context(), []string{target}, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetDefaultTarget(t *testing.T) {
+	conn := setupConn(t)
+
+	target, err := conn.GetDefaultTarget(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(target, ".target") {
+		t.Fatalf("default target %q is not a target", target)
+	}
+}
+
 // Test a global Reload
 func TestReload(t *testing.T) {
 	conn := setupConn(t)
@@ -1921,3 +2039,79 @@ func TestCancel(t *testing.T) {
 		t.Fatal("Job is not canceled:", job)
 	}
 }
+
+func TestEnqueueUnitJob(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	job, affected, err := conn.EnqueueUnitJob(t.Context(), target, "start", "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Unit() != target || job.Type() != "start" {
+		t.Fatalf("unexpected job %s/%s", job.Unit(), job.Type())
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+	for _, j := range affected {
+		if j.Path() == job.Path() {
+			t.Fatalf("requested job %s listed as affected", j.Path())
+		}
+		if err := j.Wait(t.Context()); err != nil {
+			t.Fatalf("affected job %s/%s failed: %v", j.Unit(), j.Type(), err)
+		}
+	}
+
+	job, err = conn.StopUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+}
+
+func TestEnqueueMarkedJobs(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	job, err := conn.StartUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+	defer conn.StopUnitContext(t.Context(), target, "replace", nil)
+
+	if err := conn.SetUnitPropertiesContext(t.Context(), target, true, PropMarkers("needs-restart")); err != nil {
+		t.Fatal(err)
+	}
+	markers, err := conn.GetUnitMarkers(t.Context(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(markers, []string{"needs-restart"}) {
+		t.Fatalf("markers = %v, want [needs-restart]", markers)
+	}
+
+	jobs, err := conn.EnqueueMarkedJobs(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) == 0 {
+		t.Fatal("expected a restart job")
+	}
+	for _, j := range jobs {
+		if err := j.Wait(t.Context()); err != nil {
+			t.Fatalf("job %s failed: %v", j.Path(), err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+
+	"github.com/coreos/go-systemd/v22/internal/polkit"
+)
+
+// AuthorizationError is returned when systemd, logind or machined deny a
+// call, by the dbus, login1 and machine1 packages. If its Interactive field
+// is set, polkit would have asked the user to authenticate had interactive
+// authorization been allowed, so the call can be retried with
+// [WithInteractiveAuthorization] or [Conn.SetInteractiveAuthorization].
+//
+// Use errors.Is with [ErrAccessDenied] and
+// [ErrInteractiveAuthorizationRequired] to tell the cases apart, or errors.As
+// for the details.
+type AuthorizationError = polkit.Error
+
+var (
+	// ErrAccessDenied matches all [AuthorizationError] values.
+	ErrAccessDenied = polkit.ErrAccessDenied
+
+	// ErrInteractiveAuthorizationRequired matches the [AuthorizationError]
+	// values that interactive authorization may resolve.
+	ErrInteractiveAuthorizationRequired = polkit.ErrInteractiveAuthorizationRequired
+)
+
+// WithInteractiveAuthorization returns a context that allows, or forbids,
+// interactive authorization for the calls made with it, overriding the
+// connection setting. It applies to the dbus, login1 and machine1 packages.
+//
+// With interactive authorization allowed, polkit may ask the user to
+// authenticate, e.g. with a password dialog, and the call blocks until the
+// user answered, so the context should not have a short deadline.
+func WithInteractiveAuthorization(ctx context.Context, allow bool) context.Context {
+	return polkit.WithInteractive(ctx, allow)
+}
+
+// SetInteractiveAuthorization allows or forbids interactive authorization for
+// all calls on the connection. It is forbidden by default. See
+// [WithInteractiveAuthorization].
+func (c *Conn) SetInteractiveAuthorization(allow bool) {
+	c.interactive.Store(allow)
+}
@@ -15,6 +15,15 @@
 package dbus
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/godbus/dbus/v5"
 )
 
@@ -69,6 +78,117 @@ func PropExecStart(command []string, uncleanIsFailure bool) Property {
 	}
 }
 
+// propExec builds one of the Exec* service properties. Unlike PropExecStart,
+// which predates it, it rejects an empty command instead of panicking.
+func propExec(name string, command []string, uncleanIsFailure bool) (Property, error) {
+	if len(command) == 0 || command[0] == "" {
+		return Property{}, fmt.Errorf("%s: command must not be empty", name)
+	}
+
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant([]execStart{{command[0], command, uncleanIsFailure}}),
+	}, nil
+}
+
+// PropExecStartPre sets the ExecStartPre service property. The arguments are
+// the same as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecStartPre=
+func PropExecStartPre(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecStartPre", command, uncleanIsFailure)
+}
+
+// PropExecStartPost sets the ExecStartPost service property. The arguments
+// are the same as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecStartPost=
+func PropExecStartPost(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecStartPost", command, uncleanIsFailure)
+}
+
+// PropExecCondition sets the ExecCondition service property. The arguments
+// are the same as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecCondition=
+func PropExecCondition(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecCondition", command, uncleanIsFailure)
+}
+
+// PropExecReload sets the ExecReload service property. The arguments are the
+// same as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecReload=
+func PropExecReload(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecReload", command, uncleanIsFailure)
+}
+
+// PropExecStop sets the ExecStop service property. The arguments are the same
+// as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecStop=
+func PropExecStop(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecStop", command, uncleanIsFailure)
+}
+
+// PropExecStopPost sets the ExecStopPost service property. The arguments are
+// the same as for PropExecStart, but an empty command is an error. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecStopPost=
+func PropExecStopPost(command []string, uncleanIsFailure bool) (Property, error) {
+	return propExec("ExecStopPost", command, uncleanIsFailure)
+}
+
+type execCommandEx struct {
+	Path  string   // the binary path to execute
+	Args  []string // an array with all arguments to pass to the executed command, starting with argument 0
+	Flags []string // the command flags, e.g. ignore-failure or privileged
+}
+
+// execFlags are the flags accepted by PropExecEx, corresponding to the
+// special executable prefixes of unit files.
+var execFlags = []string{
+	"ignore-failure", // "-" prefix
+	"privileged",     // "+" prefix
+	"no-setuid",      // "!" prefix
+	"ambient",        // "!!" prefix
+	"no-env-expand",  // ":" prefix
+}
+
+// ExecFlags returns the flags accepted by PropExecEx, corresponding to the
+// special executable prefixes of unit files.
+func ExecFlags() []string {
+	return slices.Clone(execFlags)
+}
+
+// PropExecEx sets the extended form of one of the Exec* service properties,
+// which accepts flags in place of the executable prefixes of unit files.
+// setting is the name of the setting without the Ex suffix, e.g. ExecStart or
+// ExecStartPre, and flags must be taken from [ExecFlags]. Requires systemd v243
+// or higher. See
+// http://www.freedesktop.org/software/systemd/man/systemd.service.html#ExecStart=
+func PropExecEx(setting string, command []string, flags ...string) (Property, error) {
+	switch setting {
+	case "ExecCondition", "ExecStartPre", "ExecStart", "ExecStartPost",
+		"ExecReload", "ExecStop", "ExecStopPost":
+	default:
+		return Property{}, fmt.Errorf("unknown Exec setting %q", setting)
+	}
+	if len(command) == 0 || command[0] == "" {
+		return Property{}, fmt.Errorf("%s: command must not be empty", setting)
+	}
+	for _, f := range flags {
+		if !slices.Contains(execFlags, f) {
+			return Property{}, fmt.Errorf("%s: unknown flag %q", setting, f)
+		}
+	}
+
+	return Property{
+		Name:  setting + "Ex",
+		Value: dbus.MakeVariant([]execCommandEx{{command[0], command, flags}}),
+	}, nil
+}
+
+// PropExecStartEx sets the ExecStartEx service property. It is a shorthand
+// for PropExecEx("ExecStart", command, flags...).
+func PropExecStartEx(command []string, flags ...string) (Property, error) {
+	return PropExecEx("ExecStart", command, flags...)
+}
+
 // PropRemainAfterExit sets the RemainAfterExit service property. See
 // http://www.freedesktop.org/software/systemd/man/systemd.service.html#RemainAfterExit=
 func PropRemainAfterExit(b bool) Property {
@@ -96,6 +216,18 @@ func PropDescription(desc string) Property {
 	}
 }
 
+// PropMarkers sets the Markers unit property, to be used with
+// [Conn.SetUnitPropertiesContext] in runtime mode. Markers are needs-restart
+// and needs-reload; prefixed with "+" or "-" they are added to or removed
+// from the current set instead of replacing it. Marked units are restarted
+// or reloaded by [Conn.EnqueueMarkedJobs].
+func PropMarkers(markers ...string) Property {
+	return Property{
+		Name:  "Markers",
+		Value: dbus.MakeVariant(markers),
+	}
+}
+
 func propDependency(name string, units []string) Property {
 	return Property{
 		Name:  name,
@@ -235,3 +367,765 @@ func PropPids(pids ...uint32) Property {
 		Value: dbus.MakeVariant(pids),
 	}
 }
+
+// ResourceInfinity may be passed to the memory and tasks resource-control
+// property builders to remove the respective limit.
+const ResourceInfinity uint64 = math.MaxUint64
+
+func propBool(name string, b bool) Property {
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+func propUint64(name string, v uint64) Property {
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(v),
+	}
+}
+
+// PropCPUAccounting sets the CPUAccounting resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#CPUAccounting=
+func PropCPUAccounting(b bool) Property {
+	return propBool("CPUAccounting", b)
+}
+
+// PropMemoryAccounting sets the MemoryAccounting resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemoryAccounting=
+func PropMemoryAccounting(b bool) Property {
+	return propBool("MemoryAccounting", b)
+}
+
+// PropIOAccounting sets the IOAccounting resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IOAccounting=
+func PropIOAccounting(b bool) Property {
+	return propBool("IOAccounting", b)
+}
+
+// PropTasksAccounting sets the TasksAccounting resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#TasksAccounting=
+func PropTasksAccounting(b bool) Property {
+	return propBool("TasksAccounting", b)
+}
+
+// PropIPAccounting sets the IPAccounting resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IPAccounting=
+func PropIPAccounting(b bool) Property {
+	return propBool("IPAccounting", b)
+}
+
+// checkLimit validates a memory or tasks limit, which systemd rejects if it
+// is zero. Zero does not remove the limit, [ResourceInfinity] does.
+func checkLimit(name string, v uint64) error {
+	if v == 0 {
+		return fmt.Errorf("%s must be at least 1, use ResourceInfinity for no limit", name)
+	}
+	return nil
+}
+
+// PropMemoryMin sets the MemoryMin resource-control property, in bytes. Zero
+// protects no memory, and [ResourceInfinity] all of it. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemoryMin=bytes
+func PropMemoryMin(bytes uint64) Property {
+	return propUint64("MemoryMin", bytes)
+}
+
+// PropMemoryLow sets the MemoryLow resource-control property, in bytes. Zero
+// protects no memory, and [ResourceInfinity] all of it. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemoryLow=bytes
+func PropMemoryLow(bytes uint64) Property {
+	return propUint64("MemoryLow", bytes)
+}
+
+// PropMemoryHigh sets the MemoryHigh resource-control property, in bytes. The
+// limit must not be zero. Pass [ResourceInfinity] for no limit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemoryHigh=bytes
+func PropMemoryHigh(bytes uint64) (Property, error) {
+	if err := checkLimit("MemoryHigh", bytes); err != nil {
+		return Property{}, err
+	}
+	return propUint64("MemoryHigh", bytes), nil
+}
+
+// PropMemoryMax sets the MemoryMax resource-control property, in bytes. The
+// limit must not be zero. Pass [ResourceInfinity] for no limit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemoryMax=bytes
+func PropMemoryMax(bytes uint64) (Property, error) {
+	if err := checkLimit("MemoryMax", bytes); err != nil {
+		return Property{}, err
+	}
+	return propUint64("MemoryMax", bytes), nil
+}
+
+// PropMemorySwapMax sets the MemorySwapMax resource-control property, in
+// bytes. Zero disables swap for the unit. Pass [ResourceInfinity] for no
+// limit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#MemorySwapMax=bytes
+func PropMemorySwapMax(bytes uint64) Property {
+	return propUint64("MemorySwapMax", bytes)
+}
+
+// PropTasksMax sets the TasksMax resource-control property. The limit must not
+// be zero. Pass [ResourceInfinity] for no limit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#TasksMax=N
+func PropTasksMax(n uint64) (Property, error) {
+	if err := checkLimit("TasksMax", n); err != nil {
+		return Property{}, err
+	}
+	return propUint64("TasksMax", n), nil
+}
+
+// PropDelegate sets the Delegate resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#Delegate=
+func PropDelegate(b bool) Property {
+	return propBool("Delegate", b)
+}
+
+// checkWeight validates a CPU or IO weight, which must be in the range
+// 1..10000.
+func checkWeight(name string, weight uint64) error {
+	if weight < 1 || weight > 10000 {
+		return fmt.Errorf("%s must be between 1 and 10000, got %d", name, weight)
+	}
+	return nil
+}
+
+// PropCPUWeight sets the CPUWeight resource-control property. The weight must
+// be between 1 and 10000. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#CPUWeight=weight
+func PropCPUWeight(weight uint64) (Property, error) {
+	if err := checkWeight("CPUWeight", weight); err != nil {
+		return Property{}, err
+	}
+	return propUint64("CPUWeight", weight), nil
+}
+
+// PropCPUQuotaPerSec sets the CPUQuotaPerSecUSec resource-control property,
+// i.e. the CPU time the unit may use per second of wall clock time. A quota of
+// 2*time.Second allows the use of two full CPUs. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#CPUQuota=
+func PropCPUQuotaPerSec(quota time.Duration) (Property, error) {
+	if quota < time.Microsecond {
+		return Property{}, fmt.Errorf("CPU quota must be at least 1µs, got %s", quota)
+	}
+	return propUint64("CPUQuotaPerSecUSec", uint64(quota.Microseconds())), nil
+}
+
+// cpuSet converts a list of CPU or NUMA node indexes into the bit mask
+// representation systemd expects on the bus.
+func cpuSet(name string, indexes []int) ([]byte, error) {
+	var mask []byte
+	for _, i := range indexes {
+		if i < 0 {
+			return nil, fmt.Errorf("%s: invalid index %d", name, i)
+		}
+		for len(mask) <= i/8 {
+			mask = append(mask, 0)
+		}
+		mask[i/8] |= 1 << (i % 8)
+	}
+	return mask, nil
+}
+
+// PropAllowedCPUs sets the AllowedCPUs resource-control property to the given
+// CPU indexes. An empty list removes the restriction. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#AllowedCPUs=
+func PropAllowedCPUs(cpus ...int) (Property, error) {
+	mask, err := cpuSet("AllowedCPUs", cpus)
+	if err != nil {
+		return Property{}, err
+	}
+	return Property{
+		Name:  "AllowedCPUs",
+		Value: dbus.MakeVariant(mask),
+	}, nil
+}
+
+// PropAllowedMemoryNodes sets the AllowedMemoryNodes resource-control
+// property to the given NUMA node indexes. An empty list removes the
+// restriction. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#AllowedMemoryNodes=
+func PropAllowedMemoryNodes(nodes ...int) (Property, error) {
+	mask, err := cpuSet("AllowedMemoryNodes", nodes)
+	if err != nil {
+		return Property{}, err
+	}
+	return Property{
+		Name:  "AllowedMemoryNodes",
+		Value: dbus.MakeVariant(mask),
+	}, nil
+}
+
+// PropIOWeight sets the IOWeight resource-control property. The weight must
+// be between 1 and 10000. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IOWeight=weight
+func PropIOWeight(weight uint64) (Property, error) {
+	if err := checkWeight("IOWeight", weight); err != nil {
+		return Property{}, err
+	}
+	return propUint64("IOWeight", weight), nil
+}
+
+type ioDeviceValue struct {
+	Path  string // the device node or a file on the block device
+	Value uint64 // the weight or limit for the device
+}
+
+func propIODevice(name string, device string, value uint64) (Property, error) {
+	if !strings.HasPrefix(device, "/") {
+		return Property{}, fmt.Errorf("%s: device must be an absolute path, got %q", name, device)
+	}
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant([]ioDeviceValue{{device, value}}),
+	}, nil
+}
+
+// PropIODeviceWeight sets the IODeviceWeight resource-control property for a
+// single device. Multiple devices may be configured by passing one property
+// per device. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IODeviceWeight=device%20weight
+func PropIODeviceWeight(device string, weight uint64) (Property, error) {
+	if err := checkWeight("IODeviceWeight", weight); err != nil {
+		return Property{}, err
+	}
+	return propIODevice("IODeviceWeight", device, weight)
+}
+
+// PropIOReadBandwidthMax sets the IOReadBandwidthMax resource-control
+// property for a single device, in bytes per second. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IOReadBandwidthMax=device%20bytes
+func PropIOReadBandwidthMax(device string, bytesPerSecond uint64) (Property, error) {
+	if bytesPerSecond == 0 {
+		return Property{}, errors.New("IOReadBandwidthMax must be greater than 0")
+	}
+	return propIODevice("IOReadBandwidthMax", device, bytesPerSecond)
+}
+
+// PropIOWriteBandwidthMax sets the IOWriteBandwidthMax resource-control
+// property for a single device, in bytes per second. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IOWriteBandwidthMax=device%20bytes
+func PropIOWriteBandwidthMax(device string, bytesPerSecond uint64) (Property, error) {
+	if bytesPerSecond == 0 {
+		return Property{}, errors.New("IOWriteBandwidthMax must be greater than 0")
+	}
+	return propIODevice("IOWriteBandwidthMax", device, bytesPerSecond)
+}
+
+type deviceAllow struct {
+	Path        string // the device node, or a char-/block- device group
+	Permissions string // a combination of r, w and m
+}
+
+// PropDeviceAllow sets the DeviceAllow resource-control property for a single
+// device node or device group (e.g. "char-pts"). permissions must be a
+// non-empty combination of r, w and m. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#DeviceAllow=
+func PropDeviceAllow(device string, permissions string) (Property, error) {
+	if !strings.HasPrefix(device, "/") &&
+		!strings.HasPrefix(device, "char-") &&
+		!strings.HasPrefix(device, "block-") {
+		return Property{}, fmt.Errorf("DeviceAllow: invalid device %q", device)
+	}
+	if permissions == "" || strings.Trim(permissions, "rwm") != "" {
+		return Property{}, fmt.Errorf("DeviceAllow: invalid permissions %q", permissions)
+	}
+	return Property{
+		Name:  "DeviceAllow",
+		Value: dbus.MakeVariant([]deviceAllow{{device, permissions}}),
+	}, nil
+}
+
+// PropDevicePolicy sets the DevicePolicy resource-control property. policy
+// must be one of auto, closed or strict. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#DevicePolicy=auto%7Cclosed%7Cstrict
+func PropDevicePolicy(policy string) (Property, error) {
+	if !slices.Contains([]string{"auto", "closed", "strict"}, policy) {
+		return Property{}, fmt.Errorf("DevicePolicy: invalid policy %q", policy)
+	}
+	return Property{
+		Name:  "DevicePolicy",
+		Value: dbus.MakeVariant(policy),
+	}, nil
+}
+
+// Address families as used by systemd on the bus. These are the Linux values,
+// independent of the platform this package is built for.
+const (
+	afInet  = 2
+	afInet6 = 10
+)
+
+type ipAddressPrefix struct {
+	Family       int32  // the address family, AF_INET or AF_INET6
+	Address      []byte // the address in network byte order
+	PrefixLength uint32 // the prefix length in bits
+}
+
+func propIPAddress(name string, prefixes []netip.Prefix) (Property, error) {
+	out := make([]ipAddressPrefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		if !p.IsValid() {
+			return Property{}, fmt.Errorf("%s: invalid prefix %s", name, p)
+		}
+		p = p.Masked()
+		family := int32(afInet6)
+		if p.Addr().Is4() {
+			family = afInet
+		}
+		out = append(out, ipAddressPrefix{family, p.Addr().AsSlice(), uint32(p.Bits())})
+	}
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(out),
+	}, nil
+}
+
+// PropIPAddressAllow sets the IPAddressAllow resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IPAddressAllow=ADDRESS%5B/PREFIXLENGTH%5D%E2%80%A6
+func PropIPAddressAllow(prefixes ...netip.Prefix) (Property, error) {
+	return propIPAddress("IPAddressAllow", prefixes)
+}
+
+// PropIPAddressDeny sets the IPAddressDeny resource-control property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#IPAddressDeny=ADDRESS%5B/PREFIXLENGTH%5D%E2%80%A6
+func PropIPAddressDeny(prefixes ...netip.Prefix) (Property, error) {
+	return propIPAddress("IPAddressDeny", prefixes)
+}
+
+func propString(name string, v string) Property {
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(v),
+	}
+}
+
+// PropUser sets the User exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#User=
+func PropUser(user string) Property {
+	return propString("User", user)
+}
+
+// PropGroup sets the Group exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#Group=
+func PropGroup(group string) Property {
+	return propString("Group", group)
+}
+
+// PropDynamicUser sets the DynamicUser exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#DynamicUser=
+func PropDynamicUser(b bool) Property {
+	return propBool("DynamicUser", b)
+}
+
+// PropWorkingDirectory sets the WorkingDirectory exec property. dir must be an
+// absolute path or "~", optionally prefixed with "-" to ignore a missing
+// directory. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#WorkingDirectory=
+func PropWorkingDirectory(dir string) (Property, error) {
+	d := strings.TrimPrefix(dir, "-")
+	if d != "~" && !strings.HasPrefix(d, "/") {
+		return Property{}, fmt.Errorf("WorkingDirectory: must be an absolute path or ~, got %q", dir)
+	}
+	return propString("WorkingDirectory", dir), nil
+}
+
+// PropEnvironment sets the Environment exec property. Every variable must be
+// of the form KEY=value. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#Environment=
+func PropEnvironment(env ...string) (Property, error) {
+	for _, e := range env {
+		key, _, ok := strings.Cut(e, "=")
+		if !ok || !validEnvKey(key) {
+			return Property{}, fmt.Errorf("Environment: invalid assignment %q", e)
+		}
+	}
+	return Property{
+		Name:  "Environment",
+		Value: dbus.MakeVariant(env),
+	}, nil
+}
+
+func validEnvKey(key string) bool {
+	if key == "" || unicode.IsDigit(rune(key[0])) {
+		return false
+	}
+	for _, r := range key {
+		if r != '_' && (r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+type environmentFile struct {
+	Path     string // the absolute path of the file
+	Optional bool   // whether a missing file is silently ignored
+}
+
+// PropEnvironmentFile adds a file to the EnvironmentFiles exec property. If
+// optional is true, a missing file is ignored. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#EnvironmentFile=
+func PropEnvironmentFile(path string, optional bool) (Property, error) {
+	if !strings.HasPrefix(path, "/") {
+		return Property{}, fmt.Errorf("EnvironmentFiles: must be an absolute path, got %q", path)
+	}
+	return Property{
+		Name:  "EnvironmentFiles",
+		Value: dbus.MakeVariant([]environmentFile{{path, optional}}),
+	}, nil
+}
+
+// propStdio builds one of the Standard* exec properties. Settings that refer
+// to files or named file descriptors are passed to systemd through dedicated
+// properties, as the plain properties only accept the argument-less values.
+func propStdio(name string, value string, plain []string, files map[string]string) (Property, error) {
+	if slices.Contains(plain, value) {
+		return propString(name, value), nil
+	}
+	if fd, ok := strings.CutPrefix(value, "fd:"); ok && fd != "" {
+		return propString(name+"FileDescriptorName", fd), nil
+	}
+	for prefix, prop := range files {
+		if path, ok := strings.CutPrefix(value, prefix); ok {
+			if !strings.HasPrefix(path, "/") {
+				return Property{}, fmt.Errorf("%s: must be an absolute path, got %q", name, path)
+			}
+			return propString(prop, path), nil
+		}
+	}
+	return Property{}, fmt.Errorf("%s: invalid value %q", name, value)
+}
+
+// PropStandardInput sets the StandardInput exec property. value may be any of
+// the values accepted in unit files, e.g. null, tty or file:/path. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#StandardInput=
+func PropStandardInput(value string) (Property, error) {
+	return propStdio("StandardInput", value,
+		[]string{"null", "tty", "tty-force", "tty-fail", "data", "socket", "fd"},
+		map[string]string{"file:": "StandardInputFile"})
+}
+
+var stdoutValues = []string{
+	"inherit", "null", "tty", "journal", "kmsg", "journal+console",
+	"kmsg+console", "socket", "fd",
+}
+
+// PropStandardOutput sets the StandardOutput exec property. value may be any
+// of the values accepted in unit files, e.g. journal, null or append:/path.
+// See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#StandardOutput=
+func PropStandardOutput(value string) (Property, error) {
+	return propStdio("StandardOutput", value, stdoutValues, map[string]string{
+		"file:":     "StandardOutputFile",
+		"append:":   "StandardOutputFileToAppend",
+		"truncate:": "StandardOutputFileToTruncate",
+	})
+}
+
+// PropStandardError sets the StandardError exec property. The accepted values
+// are the same as for PropStandardOutput. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#StandardError=
+func PropStandardError(value string) (Property, error) {
+	return propStdio("StandardError", value, stdoutValues, map[string]string{
+		"file:":     "StandardErrorFile",
+		"append:":   "StandardErrorFileToAppend",
+		"truncate:": "StandardErrorFileToTruncate",
+	})
+}
+
+// PropProtectSystem sets the ProtectSystem exec property. mode must be one of
+// no, yes, full or strict. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#ProtectSystem=
+func PropProtectSystem(mode string) (Property, error) {
+	if !slices.Contains([]string{"no", "yes", "full", "strict"}, mode) {
+		return Property{}, fmt.Errorf("ProtectSystem: invalid mode %q", mode)
+	}
+	return propString("ProtectSystem", mode), nil
+}
+
+// PropProtectHome sets the ProtectHome exec property. mode must be one of no,
+// yes, read-only or tmpfs. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#ProtectHome=
+func PropProtectHome(mode string) (Property, error) {
+	if !slices.Contains([]string{"no", "yes", "read-only", "tmpfs"}, mode) {
+		return Property{}, fmt.Errorf("ProtectHome: invalid mode %q", mode)
+	}
+	return propString("ProtectHome", mode), nil
+}
+
+// PropPrivateTmp sets the PrivateTmp exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#PrivateTmp=
+func PropPrivateTmp(b bool) Property {
+	return propBool("PrivateTmp", b)
+}
+
+// PropPrivateDevices sets the PrivateDevices exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#PrivateDevices=
+func PropPrivateDevices(b bool) Property {
+	return propBool("PrivateDevices", b)
+}
+
+// PropNoNewPrivileges sets the NoNewPrivileges exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#NoNewPrivileges=
+func PropNoNewPrivileges(b bool) Property {
+	return propBool("NoNewPrivileges", b)
+}
+
+// capabilities lists the Linux capabilities by their number.
+var capabilities = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// PropCapabilityBoundingSet sets the CapabilityBoundingSet exec property to
+// the given capabilities, e.g. CAP_NET_BIND_SERVICE. Passing no capabilities
+// drops all of them. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#CapabilityBoundingSet=
+func PropCapabilityBoundingSet(caps ...string) (Property, error) {
+	var mask uint64
+	for _, c := range caps {
+		i := slices.Index(capabilities, strings.ToUpper(c))
+		if i < 0 {
+			return Property{}, fmt.Errorf("CapabilityBoundingSet: unknown capability %q", c)
+		}
+		mask |= 1 << i
+	}
+	return propUint64("CapabilityBoundingSet", mask), nil
+}
+
+type filterList struct {
+	AllowList bool     // whether the list is an allow list rather than a deny list
+	Items     []string // the list entries
+}
+
+// PropSystemCallFilter sets the SystemCallFilter exec property. If allow is
+// true only the listed system calls (or @groups) are permitted, otherwise the
+// listed ones are denied. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#SystemCallFilter=
+func PropSystemCallFilter(allow bool, syscalls ...string) (Property, error) {
+	for _, s := range syscalls {
+		if s == "" || strings.ContainsAny(s, " \t~") {
+			return Property{}, fmt.Errorf("SystemCallFilter: invalid system call %q", s)
+		}
+	}
+	return Property{
+		Name:  "SystemCallFilter",
+		Value: dbus.MakeVariant(filterList{allow, syscalls}),
+	}, nil
+}
+
+// PropRestrictAddressFamilies sets the RestrictAddressFamilies exec property.
+// If allow is true only the listed families (e.g. AF_UNIX) may be used,
+// otherwise the listed ones are denied. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#RestrictAddressFamilies=
+func PropRestrictAddressFamilies(allow bool, families ...string) (Property, error) {
+	for _, f := range families {
+		if !strings.HasPrefix(f, "AF_") || len(f) == len("AF_") {
+			return Property{}, fmt.Errorf("RestrictAddressFamilies: invalid address family %q", f)
+		}
+	}
+	return Property{
+		Name:  "RestrictAddressFamilies",
+		Value: dbus.MakeVariant(filterList{allow, families}),
+	}, nil
+}
+
+func propPaths(name string, paths []string) (Property, error) {
+	for _, p := range paths {
+		if !strings.HasPrefix(strings.TrimLeft(p, "-+"), "/") {
+			return Property{}, fmt.Errorf("%s: must be an absolute path, got %q", name, p)
+		}
+	}
+	return Property{
+		Name:  name,
+		Value: dbus.MakeVariant(paths),
+	}, nil
+}
+
+// PropReadWritePaths sets the ReadWritePaths exec property. Paths must be
+// absolute, optionally prefixed with "-" or "+". See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#ReadWritePaths=
+func PropReadWritePaths(paths ...string) (Property, error) {
+	return propPaths("ReadWritePaths", paths)
+}
+
+// PropReadOnlyPaths sets the ReadOnlyPaths exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#ReadOnlyPaths=
+func PropReadOnlyPaths(paths ...string) (Property, error) {
+	return propPaths("ReadOnlyPaths", paths)
+}
+
+// PropInaccessiblePaths sets the InaccessiblePaths exec property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.exec.html#InaccessiblePaths=
+func PropInaccessiblePaths(paths ...string) (Property, error) {
+	return propPaths("InaccessiblePaths", paths)
+}
+
+// propUSec builds a property holding a duration in microseconds.
+func propUSec(name string, d time.Duration) (Property, error) {
+	if d < 0 {
+		return Property{}, fmt.Errorf("%s: duration must not be negative, got %s", name, d)
+	}
+	return propUint64(name, uint64(d.Microseconds())), nil
+}
+
+type timerCalendar struct {
+	Base       string // always OnCalendar
+	Expression string // the calendar specification
+}
+
+// PropOnCalendar adds a calendar trigger to a timer unit. spec is a calendar
+// event expression as described in systemd.time(7). See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnCalendar=
+func PropOnCalendar(spec string) (Property, error) {
+	if strings.TrimSpace(spec) == "" {
+		return Property{}, errors.New("OnCalendar: calendar specification must not be empty")
+	}
+	return Property{
+		Name:  "TimersCalendar",
+		Value: dbus.MakeVariant([]timerCalendar{{"OnCalendar", spec}}),
+	}, nil
+}
+
+type timerMonotonic struct {
+	Base string // the kind of timer, e.g. OnActiveSec
+	USec uint64 // the offset in microseconds
+}
+
+func propTimerMonotonic(base string, d time.Duration) (Property, error) {
+	if d < 0 {
+		return Property{}, fmt.Errorf("%s: duration must not be negative, got %s", base, d)
+	}
+	return Property{
+		Name:  "TimersMonotonic",
+		Value: dbus.MakeVariant([]timerMonotonic{{base, uint64(d.Microseconds())}}),
+	}, nil
+}
+
+// PropOnActiveSec adds a trigger to a timer unit that elapses d after the
+// timer was activated. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnActiveSec=
+func PropOnActiveSec(d time.Duration) (Property, error) {
+	return propTimerMonotonic("OnActiveSec", d)
+}
+
+// PropOnBootSec adds a trigger to a timer unit that elapses d after the
+// machine was booted. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnBootSec=
+func PropOnBootSec(d time.Duration) (Property, error) {
+	return propTimerMonotonic("OnBootSec", d)
+}
+
+// PropOnStartupSec adds a trigger to a timer unit that elapses d after the
+// service manager was started. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnStartupSec=
+func PropOnStartupSec(d time.Duration) (Property, error) {
+	return propTimerMonotonic("OnStartupSec", d)
+}
+
+// PropOnUnitActiveSec adds a trigger to a timer unit that elapses d after the
+// unit it activates was last activated. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnUnitActiveSec=
+func PropOnUnitActiveSec(d time.Duration) (Property, error) {
+	return propTimerMonotonic("OnUnitActiveSec", d)
+}
+
+// PropOnUnitInactiveSec adds a trigger to a timer unit that elapses d after
+// the unit it activates was last deactivated. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnUnitInactiveSec=
+func PropOnUnitInactiveSec(d time.Duration) (Property, error) {
+	return propTimerMonotonic("OnUnitInactiveSec", d)
+}
+
+// PropPersistent sets the Persistent timer property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#Persistent=
+func PropPersistent(b bool) Property {
+	return propBool("Persistent", b)
+}
+
+// PropWakeSystem sets the WakeSystem timer property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#WakeSystem=
+func PropWakeSystem(b bool) Property {
+	return propBool("WakeSystem", b)
+}
+
+// PropRemainAfterElapse sets the RemainAfterElapse timer property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#RemainAfterElapse=
+func PropRemainAfterElapse(b bool) Property {
+	return propBool("RemainAfterElapse", b)
+}
+
+// PropRandomizedDelaySec sets the RandomizedDelayUSec timer property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#RandomizedDelaySec=
+func PropRandomizedDelaySec(d time.Duration) (Property, error) {
+	return propUSec("RandomizedDelayUSec", d)
+}
+
+// PropAccuracySec sets the AccuracyUSec timer property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.timer.html#AccuracySec=
+func PropAccuracySec(d time.Duration) (Property, error) {
+	return propUSec("AccuracyUSec", d)
+}
+
+type pathSpec struct {
+	Type string // the kind of condition, e.g. PathExists
+	Path string // the watched path
+}
+
+func propPath(kind string, path string) (Property, error) {
+	if !strings.HasPrefix(path, "/") {
+		return Property{}, fmt.Errorf("%s: must be an absolute path, got %q", kind, path)
+	}
+	return Property{
+		Name:  "Paths",
+		Value: dbus.MakeVariant([]pathSpec{{kind, path}}),
+	}, nil
+}
+
+// PropPathExists adds a PathExists trigger to a path unit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#PathExists=
+func PropPathExists(path string) (Property, error) {
+	return propPath("PathExists", path)
+}
+
+// PropPathExistsGlob adds a PathExistsGlob trigger to a path unit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#PathExistsGlob=
+func PropPathExistsGlob(pattern string) (Property, error) {
+	return propPath("PathExistsGlob", pattern)
+}
+
+// PropPathChanged adds a PathChanged trigger to a path unit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#PathChanged=
+func PropPathChanged(path string) (Property, error) {
+	return propPath("PathChanged", path)
+}
+
+// PropPathModified adds a PathModified trigger to a path unit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#PathModified=
+func PropPathModified(path string) (Property, error) {
+	return propPath("PathModified", path)
+}
+
+// PropDirectoryNotEmpty adds a DirectoryNotEmpty trigger to a path unit. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#DirectoryNotEmpty=
+func PropDirectoryNotEmpty(path string) (Property, error) {
+	return propPath("DirectoryNotEmpty", path)
+}
+
+// PropMakeDirectory sets the MakeDirectory path property. See
+// http://www.freedesktop.org/software/systemd/man/systemd.path.html#MakeDirectory=
+func PropMakeDirectory(b bool) Property {
+	return propBool("MakeDirectory", b)
+}
@@ -0,0 +1,343 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestResourceControlSignatures ensures that the resource-control property
+// builders produce the D-Bus signatures systemd expects.
+func TestResourceControlSignatures(t *testing.T) {
+	must := func(p Property, err error) Property {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	for _, tt := range []struct {
+		prop Property
+		name string
+		sig  string
+	}{
+		{must(PropMemoryMax(1 << 30)), "MemoryMax", "t"},
+		{must(PropMemoryHigh(ResourceInfinity)), "MemoryHigh", "t"},
+		{PropMemoryLow(1 << 20), "MemoryLow", "t"},
+		{PropMemoryMin(1 << 20), "MemoryMin", "t"},
+		{PropMemorySwapMax(0), "MemorySwapMax", "t"},
+		{must(PropTasksMax(100)), "TasksMax", "t"},
+		{PropDelegate(true), "Delegate", "b"},
+		{PropCPUAccounting(true), "CPUAccounting", "b"},
+		{must(PropCPUWeight(100)), "CPUWeight", "t"},
+		{must(PropCPUQuotaPerSec(time.Second / 2)), "CPUQuotaPerSecUSec", "t"},
+		{must(PropAllowedCPUs(0, 3)), "AllowedCPUs", "ay"},
+		{must(PropAllowedMemoryNodes(0)), "AllowedMemoryNodes", "ay"},
+		{must(PropIOWeight(500)), "IOWeight", "t"},
+		{must(PropIODeviceWeight("/dev/sda", 200)), "IODeviceWeight", "a(st)"},
+		{must(PropIOReadBandwidthMax("/dev/sda", 1<<20)), "IOReadBandwidthMax", "a(st)"},
+		{must(PropIOWriteBandwidthMax("/dev/sda", 1<<20)), "IOWriteBandwidthMax", "a(st)"},
+		{must(PropDeviceAllow("/dev/null", "rw")), "DeviceAllow", "a(ss)"},
+		{must(PropDevicePolicy("closed")), "DevicePolicy", "s"},
+		{must(PropIPAddressAllow(netip.MustParsePrefix("10.0.0.0/8"))), "IPAddressAllow", "a(iayu)"},
+		{must(PropIPAddressDeny(netip.MustParsePrefix("::/0"))), "IPAddressDeny", "a(iayu)"},
+	} {
+		if tt.prop.Name != tt.name {
+			t.Errorf("got property name %q, want %q", tt.prop.Name, tt.name)
+		}
+		if sig := tt.prop.Value.Signature().String(); sig != tt.sig {
+			t.Errorf("%s: got signature %q, want %q", tt.name, sig, tt.sig)
+		}
+	}
+}
+
+func TestResourceControlValues(t *testing.T) {
+	p, err := PropAllowedCPUs(0, 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Value.Value(), []byte{0x09, 0x02}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllowedCPUs mask = %v, want %v", got, want)
+	}
+
+	p, err = PropCPUQuotaPerSec(1500 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Value.Value(); got != uint64(1500000) {
+		t.Errorf("CPUQuotaPerSecUSec = %v, want 1500000", got)
+	}
+
+	p, err = PropIPAddressAllow(netip.MustParsePrefix("192.168.1.7/24"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ipAddressPrefix{{afInet, []byte{192, 168, 1, 0}, 24}}
+	if got := p.Value.Value(); !reflect.DeepEqual(got, want) {
+		t.Errorf("IPAddressAllow = %v, want %v", got, want)
+	}
+}
+
+func TestResourceControlValidation(t *testing.T) {
+	for i, err := range []error{
+		second(PropCPUWeight(0)),
+		second(PropCPUWeight(10001)),
+		second(PropIOWeight(0)),
+		second(PropCPUQuotaPerSec(0)),
+		second(PropMemoryMax(0)),
+		second(PropMemoryHigh(0)),
+		second(PropTasksMax(0)),
+		second(PropAllowedCPUs(-1)),
+		second(PropAllowedMemoryNodes(-2)),
+		second(PropIODeviceWeight("sda", 100)),
+		second(PropIODeviceWeight("/dev/sda", 0)),
+		second(PropIOReadBandwidthMax("/dev/sda", 0)),
+		second(PropDeviceAllow("/dev/null", "")),
+		second(PropDeviceAllow("/dev/null", "rx")),
+		second(PropDeviceAllow("null", "r")),
+		second(PropDevicePolicy("open")),
+		second(PropIPAddressDeny(netip.Prefix{})),
+	} {
+		if err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func second(_ Property, err error) error {
+	return err
+}
+
+// TestStartTransientSliceWithLimits creates a transient slice with cgroup
+// limits and ensures that systemd accepted them.
+func TestStartTransientSliceWithLimits(t *testing.T) {
+	conn := setupConn(t)
+	target := fmt.Sprintf("testing-limits-%d.slice", time.Now().UnixNano())
+
+	weight, err := PropCPUWeight(200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err := PropDevicePolicy("closed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryMax, err := PropMemoryMax(64 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasksMax, err := PropTasksMax(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := []Property{memoryMax, tasksMax, weight, devices}
+
+	if err := runStartTrUnit(t, conn, TrUnitProp{target, props}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := runStopUnit(t, conn, TrUnitProp{target, nil}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	tasks, err := GetProperty[uint64](t.Context(), conn, target, "Slice", "TasksMax")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks != 32 {
+		t.Fatalf("TasksMax = %d, want 32", tasks)
+	}
+
+	unlimited, err := PropMemoryMax(ResourceInfinity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetUnitProperties(target, true, unlimited); err != nil {
+		t.Fatal(err)
+	}
+	memory, err := GetProperty[uint64](t.Context(), conn, target, "Slice", "MemoryMax")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory != ResourceInfinity {
+		t.Fatalf("MemoryMax = %d, want infinity", memory)
+	}
+}
+
+// TestExecPropertySignatures ensures that the exec, identity and sandboxing
+// property builders produce the D-Bus signatures systemd expects.
+func TestExecPropertySignatures(t *testing.T) {
+	must := func(p Property, err error) Property {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	for _, tt := range []struct {
+		prop Property
+		name string
+		sig  string
+	}{
+		{must(PropExecStartPre([]string{"/bin/true"}, false)), "ExecStartPre", "a(sasb)"},
+		{must(PropExecStartPost([]string{"/bin/true"}, false)), "ExecStartPost", "a(sasb)"},
+		{must(PropExecCondition([]string{"/bin/true"}, false)), "ExecCondition", "a(sasb)"},
+		{must(PropExecStop([]string{"/bin/true"}, true)), "ExecStop", "a(sasb)"},
+		{must(PropExecStartEx([]string{"/bin/true"}, "privileged")), "ExecStartEx", "a(sasas)"},
+		{must(PropExecEx("ExecStopPost", []string{"/bin/true"}, "ignore-failure")), "ExecStopPostEx", "a(sasas)"},
+		{PropUser("nobody"), "User", "s"},
+		{PropGroup("nogroup"), "Group", "s"},
+		{PropDynamicUser(true), "DynamicUser", "b"},
+		{must(PropWorkingDirectory("-/srv")), "WorkingDirectory", "s"},
+		{must(PropEnvironment("A=1", "B_2=")), "Environment", "as"},
+		{must(PropEnvironmentFile("/etc/default/foo", true)), "EnvironmentFiles", "a(sb)"},
+		{must(PropStandardInput("null")), "StandardInput", "s"},
+		{must(PropStandardInput("file:/etc/hosts")), "StandardInputFile", "s"},
+		{must(PropStandardOutput("journal")), "StandardOutput", "s"},
+		{must(PropStandardOutput("append:/var/log/foo")), "StandardOutputFileToAppend", "s"},
+		{must(PropStandardError("fd:stderr")), "StandardErrorFileDescriptorName", "s"},
+		{must(PropProtectSystem("strict")), "ProtectSystem", "s"},
+		{must(PropProtectHome("read-only")), "ProtectHome", "s"},
+		{PropPrivateTmp(true), "PrivateTmp", "b"},
+		{PropPrivateDevices(true), "PrivateDevices", "b"},
+		{PropNoNewPrivileges(true), "NoNewPrivileges", "b"},
+		{must(PropCapabilityBoundingSet("CAP_NET_BIND_SERVICE")), "CapabilityBoundingSet", "t"},
+		{must(PropSystemCallFilter(true, "@system-service")), "SystemCallFilter", "(bas)"},
+		{must(PropRestrictAddressFamilies(true, "AF_UNIX", "AF_INET")), "RestrictAddressFamilies", "(bas)"},
+		{must(PropReadWritePaths("/var/lib/foo", "-/run/foo")), "ReadWritePaths", "as"},
+	} {
+		if tt.prop.Name != tt.name {
+			t.Errorf("got property name %q, want %q", tt.prop.Name, tt.name)
+		}
+		if sig := tt.prop.Value.Signature().String(); sig != tt.sig {
+			t.Errorf("%s: got signature %q, want %q", tt.name, sig, tt.sig)
+		}
+	}
+}
+
+func TestCapabilityBoundingSet(t *testing.T) {
+	p, err := PropCapabilityBoundingSet("CAP_CHOWN", "cap_net_bind_service", "CAP_CHECKPOINT_RESTORE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := uint64(1<<0 | 1<<10 | 1<<40)
+	if got := p.Value.Value(); got != want {
+		t.Errorf("CapabilityBoundingSet = %#x, want %#x", got, want)
+	}
+}
+
+func TestExecPropertyValidation(t *testing.T) {
+	for i, err := range []error{
+		second(PropExecEx("ExecFoo", []string{"/bin/true"})),
+		second(PropExecStartEx(nil)),
+		second(PropExecStartPre(nil, false)),
+		second(PropExecStop([]string{}, false)),
+		second(PropExecReload([]string{""}, false)),
+		second(PropExecStartEx([]string{"/bin/true"}, "sudo")),
+		second(PropWorkingDirectory("srv")),
+		second(PropEnvironment("NOVALUE")),
+		second(PropEnvironment("1A=b")),
+		second(PropEnvironment("A-B=c")),
+		second(PropEnvironmentFile("relative", false)),
+		second(PropStandardInput("journal")),
+		second(PropStandardOutput("file:relative")),
+		second(PropStandardError("bogus")),
+		second(PropProtectSystem("maybe")),
+		second(PropProtectHome("full")),
+		second(PropCapabilityBoundingSet("CAP_FLY")),
+		second(PropSystemCallFilter(false, "~read")),
+		second(PropRestrictAddressFamilies(true, "UNIX")),
+		second(PropReadWritePaths("var/lib")),
+	} {
+		if err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestExecFlags(t *testing.T) {
+	flags := ExecFlags()
+	if _, err := PropExecStartEx([]string{"/bin/true"}, flags...); err != nil {
+		t.Fatalf("PropExecStartEx() rejected ExecFlags(): %v", err)
+	}
+
+	flags[0] = "sudo"
+	if ExecFlags()[0] == "sudo" {
+		t.Fatal("modifying the result of ExecFlags() changed the accepted flags")
+	}
+}
+
+// TestStartHardenedTransientService starts a transient service with a set of
+// sandboxing options and ensures that systemd accepted them.
+func TestStartHardenedTransientService(t *testing.T) {
+	conn := setupConn(t)
+	target := fmt.Sprintf("testing-hardened-%d.service", time.Now().UnixNano())
+
+	props := []Property{
+		PropExecStart([]string{"/bin/sleep", "400"}, false),
+		PropPrivateTmp(true),
+		PropPrivateDevices(true),
+		PropNoNewPrivileges(true),
+	}
+	for _, p := range []func() (Property, error){
+		func() (Property, error) { return PropExecStartPre([]string{"/bin/true"}, false) },
+		func() (Property, error) { return PropProtectSystem("strict") },
+		func() (Property, error) { return PropProtectHome("yes") },
+		func() (Property, error) { return PropWorkingDirectory("/") },
+		func() (Property, error) { return PropEnvironment("GREETING=hello") },
+		func() (Property, error) { return PropStandardOutput("null") },
+		func() (Property, error) { return PropCapabilityBoundingSet() },
+		func() (Property, error) { return PropRestrictAddressFamilies(true, "AF_UNIX") },
+		func() (Property, error) { return PropReadWritePaths("/tmp") },
+	} {
+		prop, err := p()
+		if err != nil {
+			t.Fatal(err)
+		}
+		props = append(props, prop)
+	}
+
+	if err := runStartTrUnit(t, conn, TrUnitProp{target, props}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := runStopUnit(t, conn, TrUnitProp{target, nil}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	service, err := conn.GetTypedServiceProperties(t.Context(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(service.Environment) != 1 || service.Environment[0] != "GREETING=hello" {
+		t.Fatalf("Environment = %v, want [GREETING=hello]", service.Environment)
+	}
+
+	protect, err := GetProperty[string](t.Context(), conn, target, "Service", "ProtectSystem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if protect != "strict" {
+		t.Fatalf("ProtectSystem = %q, want strict", protect)
+	}
+}
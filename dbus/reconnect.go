@@ -0,0 +1,360 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/internal/polkit"
+	"github.com/godbus/dbus/v5"
+)
+
+// systemdObject is a systemd object on the connection returned by conn. It
+// looks the connection up for every call, so that it keeps working after
+// reconnecting. It also sets the flag for interactive authorization and turns
+// D-Bus errors into [AuthorizationError] and [ServiceError] values.
+type systemdObject struct {
+	c    *Conn
+	conn func() *dbus.Conn
+	path dbus.ObjectPath
+}
+
+// newObject returns the systemd object at path on the connection returned by
+// conn.
+func (c *Conn) newObject(conn func() *dbus.Conn, path dbus.ObjectPath) *systemdObject {
+	return &systemdObject{c: c, conn: conn, path: path}
+}
+
+func (o *systemdObject) obj() dbus.BusObject {
+	return o.conn().Object("org.freedesktop.systemd1", o.path)
+}
+
+func (o *systemdObject) flags(ctx context.Context, flags dbus.Flags) dbus.Flags {
+	return flags | polkit.Flags(ctx, o.c.interactive.Load())
+}
+
+func (o *systemdObject) Call(method string, flags dbus.Flags, args ...any) *dbus.Call {
+	return o.CallWithContext(context.Background(), method, flags, args...)
+}
+
+func (o *systemdObject) CallWithContext(ctx context.Context, method string, flags dbus.Flags, args ...any) *dbus.Call {
+	call := o.obj().CallWithContext(ctx, method, o.flags(ctx, flags), args...)
+	call.Err = wrapError(call.Err)
+	return call
+}
+
+func (o *systemdObject) Go(method string, flags dbus.Flags, ch chan *dbus.Call, args ...any) *dbus.Call {
+	return o.GoWithContext(context.Background(), method, flags, ch, args...)
+}
+
+func (o *systemdObject) GoWithContext(ctx context.Context, method string, flags dbus.Flags, ch chan *dbus.Call, args ...any) *dbus.Call {
+	return o.obj().GoWithContext(ctx, method, o.flags(ctx, flags), ch, args...)
+}
+
+func (o *systemdObject) AddMatchSignal(iface, member string, options ...dbus.MatchOption) *dbus.Call {
+	return o.obj().AddMatchSignal(iface, member, options...)
+}
+
+func (o *systemdObject) RemoveMatchSignal(iface, member string, options ...dbus.MatchOption) *dbus.Call {
+	return o.obj().RemoveMatchSignal(iface, member, options...)
+}
+
+func (o *systemdObject) GetProperty(p string) (dbus.Variant, error) {
+	return o.obj().GetProperty(p)
+}
+
+func (o *systemdObject) StoreProperty(p string, value any) error {
+	return o.obj().StoreProperty(p, value)
+}
+
+func (o *systemdObject) SetProperty(p string, v any) error {
+	return o.obj().SetProperty(p, v)
+}
+
+func (o *systemdObject) Destination() string {
+	return "org.freedesktop.systemd1"
+}
+
+func (o *systemdObject) Path() dbus.ObjectPath {
+	return o.path
+}
+
+// ConnectionState is the state reported in a [ConnectionEvent].
+type ConnectionState int
+
+const (
+	// ConnectionLost is reported when one of the underlying connections was
+	// closed, e.g. because the bus or systemd restarted.
+	ConnectionLost ConnectionState = iota + 1
+	// ConnectionReconnecting is reported for every failed reconnect
+	// attempt.
+	ConnectionReconnecting
+	// ConnectionRestored is reported when the connection was reestablished
+	// and all subscriptions were restored.
+	ConnectionRestored
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionLost:
+		return "lost"
+	case ConnectionReconnecting:
+		return "reconnecting"
+	case ConnectionRestored:
+		return "restored"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionEvent reports a change of the connection state, see
+// [Conn.EnableReconnect].
+type ConnectionEvent struct {
+	State ConnectionState
+
+	// Err is the error of the failed attempt for ConnectionReconnecting.
+	Err error
+
+	// Attempt is the number of the reconnect attempt, starting at 1.
+	Attempt int
+
+	// LostJobs are the jobs that finished while the connection was lost,
+	// for ConnectionRestored. Their result is unknown and reported as
+	// [JobLost] to the waiters.
+	LostJobs []dbus.ObjectPath
+}
+
+// ReconnectOptions configure [Conn.EnableReconnect].
+type ReconnectOptions struct {
+	// MinBackoff is the delay before the first reconnect attempt. It
+	// doubles for every failed attempt, up to MaxBackoff. The defaults are
+	// 100ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// EnableReconnect makes the connection reconnect automatically when it is
+// lost, e.g. because the bus was restarted or, for connections made with
+// [NewSystemdConnectionContext], because systemd reexecuted. Connections
+// are made with the function passed to [NewConnection], retrying with
+// exponential backoff until [Conn.Close] is called.
+//
+// After reconnecting, match rules and the systemd subscription are restored,
+// [UnitStateCache] instances are resynchronized, and jobs that finished
+// while disconnected are completed with [JobLost]. Signals emitted while
+// disconnected are lost. Method calls made while disconnected fail.
+//
+// The returned channel receives the state changes of the connection. It is
+// buffered, and events are dropped if it is full. Calling EnableReconnect
+// again returns the same channel.
+func (c *Conn) EnableReconnect(opts ReconnectOptions) <-chan ConnectionEvent {
+	c.reconnect.Lock()
+	defer c.reconnect.Unlock()
+
+	if c.reconnect.events != nil {
+		return c.reconnect.events
+	}
+
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(30*time.Second, opts.MinBackoff)
+	}
+
+	c.reconnect.events = make(chan ConnectionEvent, signalBuffer)
+	go c.monitor(opts)
+	return c.reconnect.events
+}
+
+func (c *Conn) sendConnectionEvent(ev ConnectionEvent) {
+	select {
+	case c.reconnect.events <- ev:
+	default:
+	}
+}
+
+// addResyncHook registers f to be called after reconnecting. The returned
+// function removes it again.
+func (c *Conn) addResyncHook(f func(context.Context)) func() {
+	c.reconnect.Lock()
+	defer c.reconnect.Unlock()
+
+	key := c.reconnect.nextKey
+	c.reconnect.nextKey++
+	c.reconnect.hooks[key] = f
+
+	return func() {
+		c.reconnect.Lock()
+		defer c.reconnect.Unlock()
+		delete(c.reconnect.hooks, key)
+	}
+}
+
+// monitor waits for the connections to close, and reconnects until the
+// connection is closed with Close.
+func (c *Conn) monitor(opts ReconnectOptions) {
+	for {
+		sys, sig := c.sys(), c.sig()
+		select {
+		case <-sys.Context().Done():
+		case <-sig.Context().Done():
+		case <-c.reconnect.done:
+			return
+		}
+		sys.Close()
+		sig.Close()
+		c.sendConnectionEvent(ConnectionEvent{State: ConnectionLost})
+
+		backoff := opts.MinBackoff
+		for attempt := 1; ; attempt++ {
+			select {
+			case <-time.After(backoff):
+			case <-c.reconnect.done:
+				return
+			}
+
+			err := c.reconnectOnce()
+			if err == nil {
+				break
+			}
+			c.sendConnectionEvent(ConnectionEvent{State: ConnectionReconnecting, Err: err, Attempt: attempt})
+			backoff = min(2*backoff, opts.MaxBackoff)
+		}
+
+		lost := c.failLostJobs()
+
+		c.reconnect.Lock()
+		hooks := make([]func(context.Context), 0, len(c.reconnect.hooks))
+		for _, f := range c.reconnect.hooks {
+			hooks = append(hooks, f)
+		}
+		c.reconnect.Unlock()
+		for _, f := range hooks {
+			f(c.sys().Context())
+		}
+
+		c.sendConnectionEvent(ConnectionEvent{State: ConnectionRestored, LostJobs: lost})
+	}
+}
+
+// reconnectOnce dials new connections, restores match rules and the
+// subscription on them, and only then replaces the current connections. If
+// restoring fails, the new connections are closed and the current ones are
+// kept for the next attempt.
+func (c *Conn) reconnectOnce() error {
+	sysconn, err := c.dialBus()
+	if err != nil {
+		return err
+	}
+	sigconn, err := c.dialBus()
+	if err != nil {
+		sysconn.Close()
+		return err
+	}
+	fail := func(err error) error {
+		sysconn.Close()
+		sigconn.Close()
+		return err
+	}
+
+	// Match rules added while restoring would go to the old connection, so
+	// hold the rules until the new connections are in place.
+	c.matches.Lock()
+	defer c.matches.Unlock()
+
+	ctx := sigconn.Context()
+	for rule := range c.matches.rules {
+		if err := restoreMatch(ctx, sigconn, rule); err != nil {
+			return fail(err)
+		}
+	}
+	if c.matches.subscribed {
+		sigobj := c.newObject(func() *dbus.Conn { return sigconn }, "/org/freedesktop/systemd1")
+		if err := subscribe(ctx, sigobj); err != nil {
+			return fail(err)
+		}
+	}
+
+	c.reconnect.Lock()
+	defer c.reconnect.Unlock()
+	if c.reconnect.closed {
+		sysconn.Close()
+		sigconn.Close()
+		return nil
+	}
+
+	c.connLock.Lock()
+	c.sysconn, c.sigconn = sysconn, sigconn
+	c.connLock.Unlock()
+
+	c.dispatch()
+	return nil
+}
+
+// restoreMatch adds rule to conn. Connections made directly to systemd have
+// no bus to add match rules to, so the errors reporting that are ignored.
+func restoreMatch(ctx context.Context, conn *dbus.Conn, rule string) error {
+	err := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.AddMatch", 0, rule).Store()
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) {
+		switch dbusErr.Name {
+		case "org.freedesktop.DBus.Error.UnknownMethod",
+			"org.freedesktop.DBus.Error.UnknownObject",
+			"org.freedesktop.DBus.Error.UnknownInterface":
+			return nil
+		}
+	}
+	return err
+}
+
+// failLostJobs completes the jobs that are waited for, but no longer exist,
+// with JobLost. It returns their paths. Jobs that cannot be looked up for
+// another reason, e.g. because the connection was lost again, are kept and
+// checked again after the next reconnect.
+func (c *Conn) failLostJobs() []dbus.ObjectPath {
+	c.jobListener.Lock()
+	defer c.jobListener.Unlock()
+
+	paths := make(map[dbus.ObjectPath]bool)
+	for p := range c.jobListener.jobs {
+		paths[p] = true
+	}
+	for p := range c.jobListener.handles {
+		paths[p] = true
+	}
+
+	var lost []dbus.ObjectPath
+	for p := range paths {
+		// Jobs survive a reexec of systemd, and their JobRemoved signal is
+		// still received after reconnecting.
+		id, err := strconv.ParseUint(path.Base(string(p)), 10, 32)
+		if err == nil {
+			var jobPath dbus.ObjectPath
+			err := c.sysobj.Call("org.freedesktop.systemd1.Manager.GetJob", 0, uint32(id)).Store(&jobPath)
+			if !errors.Is(err, ErrNoSuchJob) {
+				continue
+			}
+		}
+
+		c.completeJob(p, string(JobLost))
+		lost = append(lost, p)
+	}
+	return lost
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// pipeConn returns an unauthenticated connection whose messages are
+// discarded.
+func pipeConn(t *testing.T) *dbus.Conn {
+	t.Helper()
+
+	a, b := net.Pipe()
+	go func() {
+		_, _ = io.Copy(io.Discard, b)
+	}()
+	conn, err := dbus.NewConn(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func newReconnectTestConn(t *testing.T, dial func() (*dbus.Conn, error)) *Conn {
+	c := newTestConn()
+	c.sysconn = pipeConn(t)
+	c.sigconn = pipeConn(t)
+	c.sysobj = c.newObject(c.sys, "/org/freedesktop/systemd1")
+	c.sigobj = c.newObject(c.sig, "/org/freedesktop/systemd1")
+	c.dialBus = dial
+	c.signalListeners.listeners = make(map[int]func(*dbus.Signal))
+	c.matches.rules = make(map[string]int)
+	c.reconnect.done = make(chan struct{})
+	c.reconnect.hooks = make(map[int]func(context.Context))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func nextConnectionEvent(t *testing.T, events <-chan ConnectionEvent) ConnectionEvent {
+	t.Helper()
+
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connection event")
+		return ConnectionEvent{}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	dialErr := errors.New("bus not available")
+	attempts := 0
+	c := newReconnectTestConn(t, func() (*dbus.Conn, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, dialErr
+		}
+		return pipeConn(t), nil
+	})
+
+	resynced := make(chan struct{}, 1)
+	c.addResyncHook(func(context.Context) {
+		resynced <- struct{}{}
+	})
+	removed := c.addResyncHook(func(context.Context) {
+		t.Error("removed resync hook called")
+	})
+	removed()
+
+	events := c.EnableReconnect(ReconnectOptions{MinBackoff: time.Millisecond})
+	if again := c.EnableReconnect(ReconnectOptions{}); again != events {
+		t.Error("EnableReconnect returned a different channel when called again")
+	}
+
+	old := c.sys()
+	old.Close()
+
+	if ev := nextConnectionEvent(t, events); ev.State != ConnectionLost {
+		t.Fatalf("got %v event, want %v", ev.State, ConnectionLost)
+	}
+	ev := nextConnectionEvent(t, events)
+	if ev.State != ConnectionReconnecting || ev.Attempt != 1 || !errors.Is(ev.Err, dialErr) {
+		t.Fatalf("got %+v, want failed attempt 1", ev)
+	}
+	if ev := nextConnectionEvent(t, events); ev.State != ConnectionRestored {
+		t.Fatalf("got %v event, want %v", ev.State, ConnectionRestored)
+	}
+
+	select {
+	case <-resynced:
+	default:
+		t.Error("resync hook not called")
+	}
+	if c.sys() == old {
+		t.Error("connection not replaced")
+	}
+	if c.sig().Context().Err() != nil || c.sys().Context().Err() != nil {
+		t.Error("new connections are closed")
+	}
+}
+
+func TestReconnectClose(t *testing.T) {
+	c := newReconnectTestConn(t, func() (*dbus.Conn, error) {
+		return nil, errors.New("bus not available")
+	})
+	events := c.EnableReconnect(ReconnectOptions{MinBackoff: time.Hour})
+
+	c.sig().Close()
+	if ev := nextConnectionEvent(t, events); ev.State != ConnectionLost {
+		t.Fatalf("got %v event, want %v", ev.State, ConnectionLost)
+	}
+
+	// Closing stops waiting for the next attempt.
+	c.Close()
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected %v event after Close", ev.State)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// TestReconnectRestoreFailure checks that the current connections are kept
+// if the match rules cannot be restored on the new ones.
+func TestReconnectRestoreFailure(t *testing.T) {
+	c := newReconnectTestConn(t, func() (*dbus.Conn, error) {
+		conn := pipeConn(t)
+		conn.Close()
+		return conn, nil
+	})
+	c.matches.rules[managerMatch] = 1
+	sys, sig := c.sys(), c.sig()
+
+	if err := c.reconnectOnce(); err == nil {
+		t.Fatal("reconnectOnce() succeeded on closed connections")
+	}
+	if c.sys() != sys || c.sig() != sig {
+		t.Error("connections replaced although restoring the match rules failed")
+	}
+}
+
+func TestFailLostJobs(t *testing.T) {
+	c := newReconnectTestConn(t, nil)
+	// The job can't be looked up on a closed connection, so whether it still
+	// exists is unknown and it is kept.
+	c.sys().Close()
+
+	c.jobListener.Lock()
+	job := c.newJob(0, "/org/freedesktop/systemd1/job/42", "foo.service", "start")
+	c.jobListener.Unlock()
+
+	if lost := c.failLostJobs(); len(lost) != 0 {
+		t.Errorf("lost jobs = %v, want none", lost)
+	}
+	if _, ok := job.Result(); ok {
+		t.Error("job completed although it may still exist")
+	}
+	if len(c.jobListener.handles) != 1 {
+		t.Errorf("job no longer registered")
+	}
+}
+
+func TestSystemdObject(t *testing.T) {
+	c := newReconnectTestConn(t, nil)
+	if c.sysobj.Destination() != "org.freedesktop.systemd1" || c.sysobj.Path() != "/org/freedesktop/systemd1" {
+		t.Errorf("unexpected object %s %s", c.sysobj.Destination(), c.sysobj.Path())
+	}
+
+	c.sys().Close()
+	call := c.sysobj.Go("org.freedesktop.systemd1.Manager.Reload", dbus.FlagNoReplyExpected, nil)
+	if !errors.Is(call.Err, dbus.ErrClosed) {
+		t.Errorf("Go() = %v, want %v", call.Err, dbus.ErrClosed)
+	}
+
+	// Calls go to the current connection.
+	c.connLock.Lock()
+	c.sysconn = pipeConn(t)
+	c.connLock.Unlock()
+	call = c.sysobj.Go("org.freedesktop.systemd1.Manager.Reload", dbus.FlagNoReplyExpected, nil)
+	if call.Err != nil {
+		t.Errorf("Go() = %v after replacing the connection", call.Err)
+	}
+}
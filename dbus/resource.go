@@ -0,0 +1,224 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// UsageUnknown is the value systemd reports for resource usage counters
+// that are not available, e.g. because accounting is disabled for the unit
+// or the unit is not running.
+const UsageUnknown uint64 = math.MaxUint64
+
+// ResourceUsage holds the resource accounting counters of a unit, as shown by
+// systemd-cgtop and systemctl status. Counters that are not available are
+// UsageUnknown.
+type ResourceUsage struct {
+	MemoryCurrent  uint64 // Memory in use, in bytes
+	MemoryPeak     uint64 // Highest memory use since the unit started, in bytes
+	CPUUsageNSec   uint64 // CPU time consumed, in nanoseconds
+	TasksCurrent   uint64 // Number of tasks
+	IOReadBytes    uint64 // Bytes read from block devices
+	IOWriteBytes   uint64 // Bytes written to block devices
+	IPIngressBytes uint64 // Bytes received over IP
+	IPEgressBytes  uint64 // Bytes sent over IP
+
+	// InvocationID identifies the current run of the unit. It changes when
+	// the unit is restarted, which resets the counters.
+	InvocationID []byte
+}
+
+// GetResourceUsage returns the resource usage of a service, scope, slice,
+// socket, mount or swap unit. Counters the unit does not have are
+// UsageUnknown.
+func (c *Conn) GetResourceUsage(ctx context.Context, unit string) (*ResourceUsage, error) {
+	usage := ResourceUsage{
+		MemoryCurrent:  UsageUnknown,
+		MemoryPeak:     UsageUnknown,
+		CPUUsageNSec:   UsageUnknown,
+		TasksCurrent:   UsageUnknown,
+		IOReadBytes:    UsageUnknown,
+		IOWriteBytes:   UsageUnknown,
+		IPIngressBytes: UsageUnknown,
+		IPEgressBytes:  UsageUnknown,
+	}
+	if err := c.getTypedProperties(ctx, unit, interfaceName(unitTypeInterface(unit)), &usage); err != nil {
+		return nil, err
+	}
+
+	id, err := GetProperty[[]byte](ctx, c, unit, "Unit", "InvocationID")
+	if err != nil {
+		return nil, err
+	}
+	usage.InvocationID = id
+
+	return &usage, nil
+}
+
+// ResourceSample is the resource usage of a unit at one point in time,
+// together with its change since the previous sample of the unit.
+type ResourceSample struct {
+	Unit  string
+	Time  time.Time
+	Usage *ResourceUsage
+	Err   error // The error reading the usage; if set, Usage is nil
+
+	// Elapsed is the time since the previous sample. It is zero, and so are
+	// all deltas and rates below, for the first sample of a unit, after an
+	// error and after Reset.
+	Elapsed time.Duration
+
+	// Reset is set if the counters were reset since the previous sample,
+	// because the unit was restarted.
+	Reset bool
+
+	CPUTime        time.Duration // CPU time consumed during Elapsed
+	CPUPercent     float64       // CPU use, where 100 is one CPU fully used
+	IOReadBytes    uint64        // Bytes read during Elapsed
+	IOWriteBytes   uint64        // Bytes written during Elapsed
+	IPIngressBytes uint64        // Bytes received during Elapsed
+	IPEgressBytes  uint64        // Bytes sent during Elapsed
+
+	IOReadRate    float64 // Bytes read per second
+	IOWriteRate   float64 // Bytes written per second
+	IPIngressRate float64 // Bytes received per second
+	IPEgressRate  float64 // Bytes sent per second
+}
+
+// SamplerOptions configure [Conn.SampleResourceUsage].
+type SamplerOptions struct {
+	// Units are the units to sample.
+	Units []string
+
+	// Interval is the time between samples. If zero, one second is used.
+	Interval time.Duration
+}
+
+// counterDelta returns the increase of a counter, and whether the counter
+// went backwards.
+func counterDelta(prev, cur uint64) (uint64, bool) {
+	if prev == UsageUnknown || cur == UsageUnknown {
+		return 0, false
+	}
+	if cur < prev {
+		return 0, true
+	}
+	return cur - prev, false
+}
+
+// delta fills in the deltas and rates of s from the previous sample of the
+// unit. Counters are considered reset if the invocation ID changed or any
+// counter went backwards.
+func (s *ResourceSample) delta(prev *ResourceSample) {
+	if prev == nil || prev.Usage == nil || s.Usage == nil {
+		return
+	}
+	p, u := prev.Usage, s.Usage
+
+	cpu, r1 := counterDelta(p.CPUUsageNSec, u.CPUUsageNSec)
+	ioRead, r2 := counterDelta(p.IOReadBytes, u.IOReadBytes)
+	ioWrite, r3 := counterDelta(p.IOWriteBytes, u.IOWriteBytes)
+	ipIn, r4 := counterDelta(p.IPIngressBytes, u.IPIngressBytes)
+	ipOut, r5 := counterDelta(p.IPEgressBytes, u.IPEgressBytes)
+	if !bytes.Equal(p.InvocationID, u.InvocationID) || r1 || r2 || r3 || r4 || r5 {
+		s.Reset = true
+		return
+	}
+
+	s.Elapsed = s.Time.Sub(prev.Time)
+	if s.Elapsed <= 0 {
+		s.Elapsed = 0
+		return
+	}
+	secs := s.Elapsed.Seconds()
+
+	s.CPUTime = time.Duration(cpu)
+	s.CPUPercent = float64(cpu) / float64(s.Elapsed) * 100
+	s.IOReadBytes, s.IOReadRate = ioRead, float64(ioRead)/secs
+	s.IOWriteBytes, s.IOWriteRate = ioWrite, float64(ioWrite)/secs
+	s.IPIngressBytes, s.IPIngressRate = ipIn, float64(ipIn)/secs
+	s.IPEgressBytes, s.IPEgressRate = ipOut, float64(ipOut)/secs
+}
+
+// SampleResourceUsage polls the resource usage of opts.Units every
+// opts.Interval, like systemd-cgtop, and sends the samples of each round,
+// in the order of opts.Units, on the returned channel. The first round is
+// sent right away. The channel is closed when ctx is done. Rounds the
+// receiver is not ready for are skipped.
+func (c *Conn) SampleResourceUsage(ctx context.Context, opts SamplerOptions) <-chan []ResourceSample {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ch := make(chan []ResourceSample, 1)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		prev := make(map[string]*ResourceSample, len(opts.Units))
+		for {
+			samples := c.sampleResourceUsage(ctx, opts.Units, prev)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case ch <- samples:
+			default:
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// sampleResourceUsage reads the usage of all units concurrently and computes
+// the deltas from prev, which is updated.
+func (c *Conn) sampleResourceUsage(ctx context.Context, units []string, prev map[string]*ResourceSample) []ResourceSample {
+	samples := make([]ResourceSample, len(units))
+	sem := make(chan struct{}, DefaultBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, unit := range units {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			samples[i].Unit = unit
+			samples[i].Usage, samples[i].Err = c.GetResourceUsage(ctx, unit)
+			samples[i].Time = time.Now()
+		}()
+	}
+	wg.Wait()
+
+	for i := range samples {
+		samples[i].delta(prev[units[i]])
+		s := samples[i]
+		prev[units[i]] = &s
+	}
+	return samples
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResourceSampleDelta(t *testing.T) {
+	start := time.Unix(1000, 0)
+	sample := func(at time.Duration, id byte, cpu, ioRead uint64) *ResourceSample {
+		return &ResourceSample{
+			Unit: "foo.service",
+			Time: start.Add(at),
+			Usage: &ResourceUsage{
+				CPUUsageNSec:   cpu,
+				IOReadBytes:    ioRead,
+				IOWriteBytes:   UsageUnknown,
+				IPIngressBytes: UsageUnknown,
+				IPEgressBytes:  UsageUnknown,
+				InvocationID:   []byte{id},
+			},
+		}
+	}
+
+	first := sample(0, 1, 1e9, 1000)
+	first.delta(nil)
+	if first.Elapsed != 0 || first.Reset {
+		t.Errorf("first sample has deltas: %+v", first)
+	}
+
+	s := sample(2*time.Second, 1, 2e9, 5000)
+	s.delta(first)
+	if s.Elapsed != 2*time.Second || s.Reset {
+		t.Fatalf("unexpected sample %+v", s)
+	}
+	if s.CPUTime != time.Second || s.CPUPercent != 50 {
+		t.Errorf("CPUTime %v, CPUPercent %v, want 1s and 50", s.CPUTime, s.CPUPercent)
+	}
+	if s.IOReadBytes != 4000 || s.IOReadRate != 2000 {
+		t.Errorf("IOReadBytes %v, IOReadRate %v, want 4000 and 2000", s.IOReadBytes, s.IOReadRate)
+	}
+	if s.IOWriteBytes != 0 || s.IOWriteRate != 0 {
+		t.Errorf("unknown counter has a delta: %v, %v", s.IOWriteBytes, s.IOWriteRate)
+	}
+
+	restarted := sample(3*time.Second, 2, 3e9, 6000)
+	restarted.delta(s)
+	if !restarted.Reset || restarted.Elapsed != 0 || restarted.CPUTime != 0 {
+		t.Errorf("restart with a new invocation ID not detected: %+v", restarted)
+	}
+
+	wrapped := sample(3*time.Second, 1, 1e6, 6000)
+	wrapped.delta(s)
+	if !wrapped.Reset || wrapped.CPUTime != 0 {
+		t.Errorf("counter going backwards not detected: %+v", wrapped)
+	}
+
+	failed := &ResourceSample{Unit: "foo.service", Time: start.Add(4 * time.Second), Err: errors.New("gone")}
+	failed.delta(s)
+	next := sample(5*time.Second, 1, 3e9, 6000)
+	next.delta(failed)
+	if failed.Elapsed != 0 || next.Elapsed != 0 {
+		t.Errorf("deltas computed across an error: %+v, %+v", failed, next)
+	}
+}
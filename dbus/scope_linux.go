@@ -0,0 +1,212 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ScopeOptions configure how [Conn.StartInScope] places a command.
+type ScopeOptions struct {
+	// Unit is the name of an existing unit to move the command into with
+	// [Conn.AttachProcessesToUnit], instead of creating a transient scope.
+	// The other options, except Subcgroup, are ignored then.
+	Unit string
+
+	// Subcgroup is the control group below the cgroup of Unit to move the
+	// command into. If empty, the command is moved into the cgroup of Unit.
+	Subcgroup string
+
+	// Name is the name of the transient scope, with or without the .scope
+	// suffix. If empty, a random name is chosen.
+	Name string
+
+	// Slice is the slice to create the scope in. If empty, systemd uses
+	// its default, system.slice.
+	Slice string
+
+	// Description is the description of the scope. If empty, the command
+	// line is used.
+	Description string
+
+	// MemoryMax limits the memory use of the scope, in bytes. Zero means no
+	// limit.
+	MemoryMax uint64
+
+	// CPUQuota limits the CPU time the scope may use per second, e.g.
+	// 500*time.Millisecond for half a CPU. Zero means no limit.
+	CPUQuota time.Duration
+
+	// Properties holds additional properties of the scope.
+	Properties []Property
+}
+
+// scopeProperties returns the properties of the transient scope of cmd.
+func scopeProperties(cmd *exec.Cmd, pid int, opts ScopeOptions) ([]Property, error) {
+	desc := opts.Description
+	if desc == "" {
+		desc = cmd.String()
+	}
+	props := []Property{
+		PropDescription(desc),
+		PropPids(uint32(pid)),
+	}
+	if opts.Slice != "" {
+		props = append(props, PropSlice(opts.Slice))
+	}
+	if opts.MemoryMax > 0 {
+		p, err := PropMemoryMax(opts.MemoryMax)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, p)
+	}
+	if opts.CPUQuota > 0 {
+		p, err := PropCPUQuotaPerSec(opts.CPUQuota)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, p)
+	}
+	return append(props, opts.Properties...), nil
+}
+
+// scopeName returns the name of the unit [Conn.StartInScope] moves the
+// command into.
+func scopeName(opts ScopeOptions) string {
+	switch {
+	case opts.Unit != "":
+		return opts.Unit
+	case opts.Name == "":
+		return transientName(".scope")
+	case strings.HasSuffix(opts.Name, ".scope"):
+		return opts.Name
+	default:
+		return opts.Name + ".scope"
+	}
+}
+
+// StartInScope starts cmd in its own transient scope unit, or moves it into
+// the existing unit opts.Unit. It returns the name of the unit and a function
+// that stops a transient scope, which kills any processes the command left
+// behind. The stop function does nothing for an existing unit, and does not
+// report an error if the scope is already gone, e.g. because systemd
+// collected it after all its processes exited.
+//
+// The command is started stopped: it is traced with ptrace, so that it stops
+// right after exec, before it can run or fork. It is resumed only once it was
+// moved. If that fails, the command is killed and reaped.
+//
+// StartInScope waits for cmd.Process.Pid itself until the command stopped, so
+// the process must not be reaped concurrently, e.g. by a goroutine calling
+// syscall.Wait4 for any child. The caller must still call cmd.Wait, and
+// should call the stop function after it. cmd.SysProcAttr must not enable
+// ptrace itself.
+func (c *Conn) StartInScope(ctx context.Context, cmd *exec.Cmd, opts ScopeOptions) (string, func(context.Context) error, error) {
+	name := scopeName(opts)
+	if err := checkUnitName(name); err != nil {
+		return "", nil, err
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	if cmd.SysProcAttr.Ptrace {
+		return "", nil, errors.New("dbus: StartInScope cannot start traced commands")
+	}
+	cmd.SysProcAttr.Ptrace = true
+	defer func() { cmd.SysProcAttr.Ptrace = false }()
+
+	// Only the thread that started the command may detach from it.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := cmd.Start(); err != nil {
+		return "", nil, err
+	}
+	pid := cmd.Process.Pid
+
+	var ws syscall.WaitStatus
+	_, err := syscall.Wait4(pid, &ws, 0, nil)
+	if err == nil && !ws.Stopped() {
+		err = fmt.Errorf("unexpected wait status %#x", ws)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return "", nil, fmt.Errorf("dbus: %s did not stop after exec: %w", cmd.Path, err)
+	}
+
+	if err := c.moveProcess(ctx, cmd, pid, name, opts); err != nil {
+		_ = cmd.Process.Kill()
+		_ = syscall.PtraceDetach(pid)
+		_ = cmd.Wait()
+		return "", nil, err
+	}
+
+	if err := syscall.PtraceDetach(pid); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return "", nil, fmt.Errorf("dbus: resuming %s: %w", cmd.Path, err)
+	}
+
+	if opts.Unit != "" {
+		return name, func(context.Context) error { return nil }, nil
+	}
+	return name, func(ctx context.Context) error { return c.stopScope(ctx, name) }, nil
+}
+
+// stopScope stops the transient scope name and waits for it to stop.
+func (c *Conn) stopScope(ctx context.Context, name string) error {
+	job, err := c.StopUnitJob(ctx, name, "replace")
+	if errors.Is(err, ErrNoSuchUnit) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := job.Wait(ctx); err != nil {
+		return fmt.Errorf("dbus: stopping %s: %w", name, err)
+	}
+	return nil
+}
+
+// moveProcess moves the stopped process into a new scope, or into the
+// existing opts.Unit.
+func (c *Conn) moveProcess(ctx context.Context, cmd *exec.Cmd, pid int, name string, opts ScopeOptions) error {
+	if opts.Unit != "" {
+		return c.AttachProcessesToUnit(ctx, name, opts.Subcgroup, []uint32{uint32(pid)})
+	}
+
+	props, err := scopeProperties(cmd, pid, opts)
+	if err != nil {
+		return err
+	}
+	job, err := c.StartTransientUnitJob(ctx, name, "fail", props, nil)
+	if err != nil {
+		return err
+	}
+	if err := job.Wait(ctx); err != nil {
+		return fmt.Errorf("dbus: starting %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScopeName(t *testing.T) {
+	for _, tt := range []struct {
+		opts ScopeOptions
+		want string
+	}{
+		{ScopeOptions{Name: "echo"}, "echo.scope"},
+		{ScopeOptions{Name: "echo.scope"}, "echo.scope"},
+		{ScopeOptions{Name: "echo.service"}, "echo.service.scope"},
+		{ScopeOptions{Unit: "foo.service", Name: "echo"}, "foo.service"},
+	} {
+		if got := scopeName(tt.opts); got != tt.want {
+			t.Errorf("scopeName(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+
+	name := scopeName(ScopeOptions{})
+	if !strings.HasPrefix(name, "run-r") || !strings.HasSuffix(name, ".scope") {
+		t.Errorf("unexpected random name %q", name)
+	}
+	if err := checkUnitName(name); err != nil {
+		t.Errorf("random name %q is invalid: %v", name, err)
+	}
+}
+
+func TestScopeProperties(t *testing.T) {
+	cmd := exec.Command("/bin/echo", "hello")
+	quota, err := PropCPUQuotaPerSec(500 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryMax, err := PropMemoryMax(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		opts ScopeOptions
+		want []Property
+	}{
+		{
+			ScopeOptions{},
+			[]Property{PropDescription("/bin/echo hello"), PropPids(42)},
+		},
+		{
+			ScopeOptions{
+				Description: "echo",
+				Slice:       "test.slice",
+				MemoryMax:   1 << 20,
+				CPUQuota:    500 * time.Millisecond,
+				Properties:  []Property{PropRemainAfterExit(true)},
+			},
+			[]Property{
+				PropDescription("echo"),
+				PropPids(42),
+				PropSlice("test.slice"),
+				memoryMax,
+				quota,
+				PropRemainAfterExit(true),
+			},
+		},
+	} {
+		got, err := scopeProperties(cmd, 42, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scopeProperties(%+v) = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+
+	if _, err := scopeProperties(cmd, 42, ScopeOptions{CPUQuota: time.Nanosecond}); err == nil {
+		t.Error("scopeProperties() accepted a CPU quota below 1µs")
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const unitPropertiesMatch = "type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',path_namespace='/org/freedesktop/systemd1/unit'"
+
+// BackpressurePolicy selects what a [Subscriber] does with an update when
+// its buffer is full.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock never discards updates: while the buffer is full,
+	// further updates are queued for the subscriber until the receiver
+	// makes room. Signal processing for the connection never waits for the
+	// receiver, but the queue grows without bounds if it does not receive.
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDropOldest discards the oldest buffered update to make
+	// room for the new one.
+	BackpressureDropOldest
+	// BackpressureDropNewest discards the new update.
+	BackpressureDropNewest
+)
+
+// SubscriberOptions configure a [Subscriber].
+type SubscriberOptions struct {
+	// Units are glob patterns, in the syntax of path.Match, selecting the
+	// units to receive updates for, e.g. "*.timer" or "foo@*.service". If
+	// empty, updates for all units are received.
+	Units []string
+
+	// Interfaces are the D-Bus interfaces to receive property changes for,
+	// either as full names or without the "org.freedesktop.systemd1."
+	// prefix, e.g. "Unit", "Service" or "Timer". If empty, changes of all
+	// interfaces are received.
+	Interfaces []string
+
+	// Buffer is the capacity of the update channel. With the drop policies,
+	// it is at least 1.
+	Buffer int
+
+	// Policy selects what happens to updates when the buffer is full.
+	Policy BackpressurePolicy
+}
+
+// UnitUpdate holds the properties of a unit that changed on one D-Bus
+// interface.
+type UnitUpdate struct {
+	Unit      string                  // The unit name
+	Interface string                  // The D-Bus interface, e.g. org.freedesktop.systemd1.Service
+	Changed   map[string]dbus.Variant // The changed properties and their new values
+}
+
+// Subscriber receives property changes of units, as returned by
+// [Conn.NewSubscriber].
+type Subscriber struct {
+	ch         chan *UnitUpdate
+	done       chan struct{}
+	units      []string
+	interfaces map[string]bool
+	policy     BackpressurePolicy
+	dropped    atomic.Uint64
+
+	// With BackpressureBlock, updates are queued and sent to ch by a
+	// goroutine, which closes stopped when it returns.
+	mu      sync.Mutex
+	queue   []*UnitUpdate
+	wake    chan struct{}
+	stopped chan struct{}
+}
+
+// Updates returns the channel receiving the updates. It is closed when the
+// subscriber is canceled.
+func (s *Subscriber) Updates() <-chan *UnitUpdate {
+	return s.ch
+}
+
+// Dropped returns the number of updates discarded because the buffer was
+// full.
+func (s *Subscriber) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func newSubscriber(opts SubscriberOptions) (*Subscriber, error) {
+	for _, pattern := range opts.Units {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("dbus: invalid unit pattern %q: %w", pattern, err)
+		}
+	}
+
+	buffer := opts.Buffer
+	if opts.Policy != BackpressureBlock {
+		buffer = max(buffer, 1)
+	}
+
+	s := &Subscriber{
+		ch:     make(chan *UnitUpdate, buffer),
+		done:   make(chan struct{}),
+		units:  opts.Units,
+		policy: opts.Policy,
+	}
+	if len(opts.Interfaces) > 0 {
+		s.interfaces = make(map[string]bool, len(opts.Interfaces))
+		for _, iface := range opts.Interfaces {
+			if !strings.Contains(iface, ".") {
+				iface = "org.freedesktop.systemd1." + iface
+			}
+			s.interfaces[iface] = true
+		}
+	}
+	if s.policy == BackpressureBlock {
+		s.wake = make(chan struct{}, 1)
+		s.stopped = make(chan struct{})
+		go s.send()
+	}
+	return s, nil
+}
+
+// send delivers the queued updates of a blocking subscriber in order, until
+// the subscriber is canceled.
+func (s *Subscriber) send() {
+	defer close(s.stopped)
+
+	for {
+		s.mu.Lock()
+		pending := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+
+		for _, u := range pending {
+			select {
+			case s.ch <- u:
+			case <-s.done:
+				return
+			}
+		}
+
+		select {
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Subscriber) matches(u *UnitUpdate) bool {
+	if s.interfaces != nil && !s.interfaces[u.Interface] {
+		return false
+	}
+	if len(s.units) == 0 {
+		return true
+	}
+	for _, pattern := range s.units {
+		if ok, _ := path.Match(pattern, u.Unit); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stop ends the deliveries to the subscriber and closes the update channel.
+// The signal listener must have been removed already.
+func (s *Subscriber) stop() {
+	close(s.done)
+	if s.stopped != nil {
+		<-s.stopped
+	}
+	close(s.ch)
+}
+
+// deliver sends u to the subscriber, applying the backpressure policy. It
+// is only called from the dispatch goroutine and never blocks.
+func (s *Subscriber) deliver(u *UnitUpdate) {
+	switch s.policy {
+	case BackpressureDropNewest:
+		select {
+		case s.ch <- u:
+		default:
+			s.dropped.Add(1)
+		}
+	case BackpressureDropOldest:
+		for {
+			select {
+			case s.ch <- u:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		s.mu.Lock()
+		s.queue = append(s.queue, u)
+		s.mu.Unlock()
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// handle is called from the dispatch goroutine for every signal.
+func (s *Subscriber) handle(signal *dbus.Signal) {
+	if signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" ||
+		!strings.HasPrefix(string(signal.Path), "/org/freedesktop/systemd1/unit/") {
+		return
+	}
+
+	u := &UnitUpdate{Unit: unitName(signal.Path)}
+	var invalidated []string
+	if dbus.Store(signal.Body, &u.Interface, &u.Changed, &invalidated) != nil {
+		return
+	}
+	if s.matches(u) {
+		s.deliver(u)
+	}
+}
+
+// NewSubscriber registers a subscriber for property changes of units. Any
+// number of subscribers can be registered, each with its own filters and
+// buffer. The subscription is canceled and the update channel closed when ctx
+// is done or the returned function is called, whichever happens first.
+//
+// Unlike [Conn.SetPropertiesSubscriber], this does not require calling
+// [Conn.Subscribe] first.
+func (c *Conn) NewSubscriber(ctx context.Context, opts SubscriberOptions) (*Subscriber, func(), error) {
+	s, err := newSubscriber(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.addMatch(ctx, unitPropertiesMatch)
+	if err := c.subscribeSignals(ctx); err != nil {
+		c.removeMatch(unitPropertiesMatch)
+		return nil, nil, err
+	}
+	remove := c.addSignalListener(s.handle)
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			remove()
+			c.removeMatch(unitPropertiesMatch)
+			s.stop()
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-s.done:
+		}
+	}()
+	return s, cancel, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func serviceChangedSignal(unit string, iface string, state string) *dbus.Signal {
+	return &dbus.Signal{
+		Path: unitPath(unit),
+		Name: "org.freedesktop.DBus.Properties.PropertiesChanged",
+		Body: []any{iface, map[string]dbus.Variant{"SubState": dbus.MakeVariant(state)}, []string{}},
+	}
+}
+
+func TestSubscriberFilter(t *testing.T) {
+	s, err := newSubscriber(SubscriberOptions{
+		Units:      []string{"foo@*.service", "*.timer"},
+		Interfaces: []string{"Service", "org.freedesktop.systemd1.Timer"},
+		Buffer:     10,
+		Policy:     BackpressureDropNewest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.handle(serviceChangedSignal("foo@1.service", "org.freedesktop.systemd1.Service", "running"))
+	s.handle(serviceChangedSignal("foo@1.service", "org.freedesktop.systemd1.Unit", "running"))
+	s.handle(serviceChangedSignal("bar.service", "org.freedesktop.systemd1.Service", "running"))
+	s.handle(serviceChangedSignal("daily.timer", "org.freedesktop.systemd1.Timer", "waiting"))
+	s.handle(&dbus.Signal{Name: "org.freedesktop.systemd1.Manager.Reloading", Body: []any{true}})
+
+	if len(s.ch) != 2 {
+		t.Fatalf("got %d updates, want 2", len(s.ch))
+	}
+	u := <-s.ch
+	if u.Unit != "foo@1.service" || u.Interface != "org.freedesktop.systemd1.Service" || u.Changed["SubState"].Value() != "running" {
+		t.Fatalf("unexpected update %+v", u)
+	}
+	if u := <-s.ch; u.Unit != "daily.timer" {
+		t.Fatalf("unexpected update %+v", u)
+	}
+
+	if _, err := newSubscriber(SubscriberOptions{Units: []string{"["}}); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
+
+func TestSubscriberBackpressure(t *testing.T) {
+	states := []string{"a", "b", "c", "d"}
+
+	s, _ := newSubscriber(SubscriberOptions{Buffer: 2, Policy: BackpressureDropNewest})
+	for _, st := range states {
+		s.handle(serviceChangedSignal("x.service", "org.freedesktop.systemd1.Unit", st))
+	}
+	if s.Dropped() != 2 {
+		t.Fatalf("drop-newest dropped %d, want 2", s.Dropped())
+	}
+	if u := <-s.ch; u.Changed["SubState"].Value() != "a" {
+		t.Fatalf("drop-newest kept %v first, want a", u.Changed["SubState"])
+	}
+
+	s, _ = newSubscriber(SubscriberOptions{Buffer: 2, Policy: BackpressureDropOldest})
+	for _, st := range states {
+		s.handle(serviceChangedSignal("x.service", "org.freedesktop.systemd1.Unit", st))
+	}
+	if s.Dropped() != 2 {
+		t.Fatalf("drop-oldest dropped %d, want 2", s.Dropped())
+	}
+	if u := <-s.ch; u.Changed["SubState"].Value() != "c" {
+		t.Fatalf("drop-oldest kept %v first, want c", u.Changed["SubState"])
+	}
+
+	// Blocking delivery queues updates instead of waiting for the receiver.
+	s, _ = newSubscriber(SubscriberOptions{Buffer: 1, Policy: BackpressureBlock})
+	defer s.stop()
+	delivered := make(chan struct{})
+	go func() {
+		for _, st := range states {
+			s.handle(serviceChangedSignal("x.service", "org.freedesktop.systemd1.Unit", st))
+		}
+		close(delivered)
+	}()
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocking delivery waited for the receiver")
+	}
+	for _, st := range states {
+		if u := <-s.ch; u.Changed["SubState"].Value() != st {
+			t.Fatalf("block delivered %v, want %s", u.Changed["SubState"], st)
+		}
+	}
+	if s.Dropped() != 0 {
+		t.Fatalf("block dropped %d updates", s.Dropped())
+	}
+}
+
+func TestNewSubscriber(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	services, cancelServices, err := conn.NewSubscriber(t.Context(), SubscriberOptions{
+		Units:      []string{"start-*.service"},
+		Interfaces: []string{"Service"},
+		Buffer:     100,
+		Policy:     BackpressureDropOldest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelServices()
+	units, cancelUnits, err := conn.NewSubscriber(t.Context(), SubscriberOptions{
+		Units:  []string{target},
+		Buffer: 100,
+		Policy: BackpressureDropNewest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := conn.StartUnitJob(t.Context(), target, "replace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(t.Context(), target, "replace", nil)
+
+	for _, s := range []*Subscriber{services, units} {
+		select {
+		case u := <-s.Updates():
+			if u.Unit != target {
+				t.Fatalf("unexpected update %+v", u)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("missing update")
+		}
+	}
+
+	cancelUnits()
+	for range units.Updates() {
+	}
+}
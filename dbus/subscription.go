@@ -17,6 +17,7 @@ package dbus
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -31,25 +32,62 @@ const (
 // Subscribe sets up this connection to subscribe to all systemd dbus events.
 // This is required before calling SubscribeUnits. When the connection closes
 // systemd will automatically stop sending signals so there is no need to
-// explicitly call Unsubscribe().
+// explicitly call Unsubscribe(). Use [Conn.Events] to receive the manager
+// signals as typed events.
 func (c *Conn) Subscribe() error {
-	c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
-		"type='signal',interface='org.freedesktop.systemd1.Manager',member='UnitNew'")
-	c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
+	c.addMatch(context.Background(), managerMatch)
+	c.addMatch(context.Background(),
 		"type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged'")
 
-	return c.sigobj.Call("org.freedesktop.systemd1.Manager.Subscribe", 0).Store()
+	err := c.sigobj.Call("org.freedesktop.systemd1.Manager.Subscribe", 0).Store()
+	if err == nil {
+		c.setSubscribed(true)
+	}
+	return err
 }
 
 // Unsubscribe this connection from systemd dbus events.
 func (c *Conn) Unsubscribe() error {
-	return c.sigobj.Call("org.freedesktop.systemd1.Manager.Unsubscribe", 0).Store()
+	err := c.sigobj.Call("org.freedesktop.systemd1.Manager.Unsubscribe", 0).Store()
+	if err == nil {
+		c.setSubscribed(false)
+	}
+	return err
+}
+
+// addMatch adds a match rule for signals to the signal connection. Rules are
+// recorded, so that they can be added again after reconnecting. Errors are
+// ignored, as there is no bus to add the match to when talking to systemd
+// directly.
+func (c *Conn) addMatch(ctx context.Context, rule string) {
+	c.matches.Lock()
+	c.matches.rules[rule]++
+	c.matches.Unlock()
+
+	c.sig().BusObject().CallWithContext(ctx, "org.freedesktop.DBus.AddMatch", 0, rule)
+}
+
+// removeMatch removes a match rule added with addMatch.
+func (c *Conn) removeMatch(rule string) {
+	c.matches.Lock()
+	if c.matches.rules[rule]--; c.matches.rules[rule] <= 0 {
+		delete(c.matches.rules, rule)
+	}
+	c.matches.Unlock()
+
+	c.sig().BusObject().Call("org.freedesktop.DBus.RemoveMatch", 0, rule)
+}
+
+func (c *Conn) setSubscribed(subscribed bool) {
+	c.matches.Lock()
+	defer c.matches.Unlock()
+	c.matches.subscribed = subscribed
 }
 
 func (c *Conn) dispatch() {
 	ch := make(chan *dbus.Signal, signalBuffer)
 
-	c.sigconn.Signal(ch)
+	c.sig().Signal(ch)
 
 	go func() {
 		for {
@@ -62,6 +100,8 @@ func (c *Conn) dispatch() {
 				c.jobComplete(signal)
 			}
 
+			c.notifySignalListeners(signal)
+
 			if c.subStateSubscriber.updateCh == nil &&
 				c.propertiesSubscriber.updateCh == nil {
 				continue
@@ -95,6 +135,84 @@ func (c *Conn) dispatch() {
 	}()
 }
 
+// addSignalListener registers f to be called from the dispatch goroutine for
+// every signal received on the signal connection. f must not block. The
+// returned function removes the listener again.
+func (c *Conn) addSignalListener(f func(*dbus.Signal)) func() {
+	c.signalListeners.Lock()
+	defer c.signalListeners.Unlock()
+
+	id := c.signalListeners.next
+	c.signalListeners.next++
+	c.signalListeners.listeners[id] = f
+
+	return func() {
+		c.signalListeners.Lock()
+		defer c.signalListeners.Unlock()
+		delete(c.signalListeners.listeners, id)
+	}
+}
+
+func (c *Conn) notifySignalListeners(signal *dbus.Signal) {
+	c.signalListeners.Lock()
+	defer c.signalListeners.Unlock()
+
+	for _, f := range c.signalListeners.listeners {
+		f(signal)
+	}
+}
+
+// subscribeSignals makes sure systemd sends signals to this connection,
+// without failing if Subscribe was called before.
+func (c *Conn) subscribeSignals(ctx context.Context) error {
+	err := subscribe(ctx, c.sigobj)
+	if err == nil {
+		c.setSubscribed(true)
+	}
+	return err
+}
+
+// subscribe calls Subscribe on the systemd manager object obj, without
+// failing if it was subscribed already.
+func subscribe(ctx context.Context, obj dbus.BusObject) error {
+	err := obj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Subscribe", 0).Store()
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) && dbusErr.Name == "org.freedesktop.systemd1.AlreadySubscribed" {
+		return nil
+	}
+	return err
+}
+
+// watchUnitProperties returns a channel that receives a value whenever the
+// properties of the unit at path change. Notifications are coalesced, so the
+// receiver should re-read the properties it is interested in. The returned
+// function stops the watch.
+func (c *Conn) watchUnitProperties(ctx context.Context, path dbus.ObjectPath) (<-chan struct{}, func(), error) {
+	match := fmt.Sprintf("type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',path='%s'", path)
+	c.addMatch(ctx, match)
+	if err := c.subscribeSignals(ctx); err != nil {
+		c.removeMatch(match)
+		return nil, nil, err
+	}
+
+	kick := make(chan struct{}, 1)
+	remove := c.addSignalListener(func(signal *dbus.Signal) {
+		if signal.Path != path || signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" {
+			return
+		}
+		select {
+		case kick <- struct{}{}:
+		default:
+		}
+	})
+
+	stop := func() {
+		remove()
+		c.removeMatch(match)
+	}
+	return kick, stop, nil
+}
+
 // Deprecated: use SubscribeUnitsContext instead.
 func (c *Conn) SubscribeUnits(interval time.Duration) (<-chan map[string]*UnitStatus, <-chan error) {
 	return c.SubscribeUnitsContext(context.Background(), interval)
@@ -127,29 +245,8 @@ func (c *Conn) SubscribeUnitsCustomContext(ctx context.Context, interval time.Du
 
 			units, err := c.ListUnitsContext(ctx)
 			if err == nil {
-				cur := make(map[string]*UnitStatus)
-				for i := range units {
-					if filterUnit != nil && filterUnit(units[i].Name) {
-						continue
-					}
-					cur[units[i].Name] = &units[i]
-				}
-
-				// add all new or changed units
-				changed := make(map[string]*UnitStatus)
-				for n, u := range cur {
-					if oldU, ok := old[n]; !ok || isChanged(oldU, u) {
-						changed[n] = u
-					}
-					delete(old, n)
-				}
-
-				// add all deleted units
-				for oldN := range old {
-					changed[oldN] = nil
-				}
-
-				old = cur
+				var changed map[string]*UnitStatus
+				old, changed = diffUnits(old, units, isChanged, filterUnit)
 
 				if len(changed) != 0 {
 					select {
@@ -178,6 +275,36 @@ func (c *Conn) SubscribeUnitsCustomContext(ctx context.Context, interval time.Du
 	return statusChan, errChan
 }
 
+// diffUnits compares the listed units to the units of the previous
+// iteration in old. It returns the current units, and the units that are
+// new or changed according to isChanged. Deleted units are returned as nil.
+// old is modified in place.
+func diffUnits(old map[string]*UnitStatus, units []UnitStatus, isChanged func(*UnitStatus, *UnitStatus) bool, filterUnit func(string) bool) (map[string]*UnitStatus, map[string]*UnitStatus) {
+	cur := make(map[string]*UnitStatus)
+	for i := range units {
+		if filterUnit != nil && filterUnit(units[i].Name) {
+			continue
+		}
+		cur[units[i].Name] = &units[i]
+	}
+
+	// add all new or changed units
+	changed := make(map[string]*UnitStatus)
+	for n, u := range cur {
+		if oldU, ok := old[n]; !ok || isChanged(oldU, u) {
+			changed[n] = u
+		}
+		delete(old, n)
+	}
+
+	// add all deleted units
+	for oldN := range old {
+		changed[oldN] = nil
+	}
+
+	return cur, changed
+}
+
 type SubStateUpdate struct {
 	UnitName string
 	SubState string
@@ -325,7 +452,8 @@ type PropertiesUpdate struct {
 // transitions will be "missed" (as they might be with SetSubStateSubscriber).
 // However, state changes will only be written to the channel with non-blocking
 // writes.  If updateCh is full, it attempts to write an error to errCh; if
-// errCh is full, the error passes silently.
+// errCh is full, the error passes silently. Only one subscriber can be set;
+// use [Conn.NewSubscriber] to register several with their own filters.
 func (c *Conn) SetPropertiesSubscriber(updateCh chan<- *PropertiesUpdate, errCh chan<- error) {
 	c.propertiesSubscriber.Lock()
 	defer c.propertiesSubscriber.Unlock()
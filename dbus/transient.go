@@ -0,0 +1,84 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// transientName returns a random unit name with the given suffix, in the
+// style of the names systemd-run generates.
+func transientName(suffix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "run-r" + hex.EncodeToString(b) + suffix
+}
+
+// triggerNames returns the names of a trigger unit and of the service it
+// activates. An empty name is replaced by a random one.
+func triggerNames(name string, suffix string) (string, string, error) {
+	if name == "" {
+		name = transientName(suffix)
+	}
+	if !strings.HasSuffix(name, suffix) {
+		if strings.Contains(name, ".") {
+			return "", "", fmt.Errorf("invalid unit name %q: must end in %s", name, suffix)
+		}
+		name += suffix
+	}
+	return name, strings.TrimSuffix(name, suffix) + ".service", nil
+}
+
+func (c *Conn) startTransientTrigger(ctx context.Context, suffix, name, mode string, trigger, service []Property) (string, string, Job, error) {
+	triggerName, serviceName, err := triggerNames(name, suffix)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	aux := []PropertyCollection{{Name: serviceName, Properties: service}}
+	job, err := c.StartTransientUnitJob(ctx, triggerName, mode, trigger, aux)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return triggerName, serviceName, job, nil
+}
+
+// StartTransientTimer creates and starts a transient timer unit together with
+// the transient service it activates, like systemd-run --on-calendar and
+// friends. name is the name of the timer, with or without the .timer suffix;
+// if empty, a random name is chosen. The service is named after the timer.
+// timer holds the timer properties, e.g. built with [PropOnCalendar],
+// [PropOnActiveSec] or [PropPersistent], and service the properties of the
+// service, e.g. built with [PropExecStart].
+//
+// The names of the timer and the service are returned, together with the job
+// starting the timer.
+func (c *Conn) StartTransientTimer(ctx context.Context, name string, mode string, timer []Property, service []Property) (string, string, Job, error) {
+	return c.startTransientTrigger(ctx, ".timer", name, mode, timer, service)
+}
+
+// StartTransientPathUnit creates and starts a transient path unit together
+// with the transient service it activates, like systemd-run --path-property.
+// It behaves like [Conn.StartTransientTimer], with path holding the path unit
+// properties, e.g. built with [PropPathExists], [PropPathChanged] or
+// [PropDirectoryNotEmpty].
+func (c *Conn) StartTransientPathUnit(ctx context.Context, name string, mode string, path []Property, service []Property) (string, string, Job, error) {
+	return c.startTransientTrigger(ctx, ".path", name, mode, path, service)
+}
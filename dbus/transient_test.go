@@ -0,0 +1,195 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTriggerNames(t *testing.T) {
+	for _, tt := range []struct {
+		name, suffix        string
+		trigger, service    string
+		wantErr, wantRandom bool
+	}{
+		{name: "backup.timer", suffix: ".timer", trigger: "backup.timer", service: "backup.service"},
+		{name: "backup", suffix: ".timer", trigger: "backup.timer", service: "backup.service"},
+		{name: "watch@foo.path", suffix: ".path", trigger: "watch@foo.path", service: "watch@foo.service"},
+		{name: "backup.service", suffix: ".timer", wantErr: true},
+		{name: "", suffix: ".path", wantRandom: true},
+	} {
+		trigger, service, err := triggerNames(tt.name, tt.suffix)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("triggerNames(%q) should have failed", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("triggerNames(%q): %v", tt.name, err)
+			continue
+		}
+		if tt.wantRandom {
+			if !strings.HasPrefix(trigger, "run-r") || !strings.HasSuffix(trigger, tt.suffix) ||
+				service != strings.TrimSuffix(trigger, tt.suffix)+".service" {
+				t.Errorf("unexpected random names %q, %q", trigger, service)
+			}
+			continue
+		}
+		if trigger != tt.trigger || service != tt.service {
+			t.Errorf("triggerNames(%q) = %q, %q, want %q, %q", tt.name, trigger, service, tt.trigger, tt.service)
+		}
+	}
+}
+
+func TestTriggerPropertySignatures(t *testing.T) {
+	for _, tt := range []struct {
+		prop func() (Property, error)
+		name string
+		sig  string
+	}{
+		{func() (Property, error) { return PropOnCalendar("*-*-* 04:00:00") }, "TimersCalendar", "a(ss)"},
+		{func() (Property, error) { return PropOnActiveSec(time.Minute) }, "TimersMonotonic", "a(st)"},
+		{func() (Property, error) { return PropOnBootSec(time.Minute) }, "TimersMonotonic", "a(st)"},
+		{func() (Property, error) { return PropOnUnitActiveSec(time.Hour) }, "TimersMonotonic", "a(st)"},
+		{func() (Property, error) { return PropRandomizedDelaySec(time.Second) }, "RandomizedDelayUSec", "t"},
+		{func() (Property, error) { return PropAccuracySec(time.Second) }, "AccuracyUSec", "t"},
+		{func() (Property, error) { return PropPathExists("/run/foo") }, "Paths", "a(ss)"},
+		{func() (Property, error) { return PropPathChanged("/etc/foo.conf") }, "Paths", "a(ss)"},
+		{func() (Property, error) { return PropDirectoryNotEmpty("/var/spool/foo") }, "Paths", "a(ss)"},
+	} {
+		prop, err := tt.prop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prop.Name != tt.name {
+			t.Errorf("got property name %q, want %q", prop.Name, tt.name)
+		}
+		if sig := prop.Value.Signature().String(); sig != tt.sig {
+			t.Errorf("%s: got signature %q, want %q", tt.name, sig, tt.sig)
+		}
+	}
+
+	for i, err := range []error{
+		second(PropOnCalendar(" ")),
+		second(PropOnActiveSec(-time.Second)),
+		second(PropAccuracySec(-time.Second)),
+		second(PropPathExists("relative")),
+	} {
+		if err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+// TestStartTransientTimer starts a transient timer that fires right away and
+// ensures that it activates its service.
+func TestStartTransientTimer(t *testing.T) {
+	conn := setupConn(t)
+
+	onActive, err := PropOnActiveSec(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accuracy, err := PropAccuracySec(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := []Property{
+		PropExecStart([]string{"/bin/true"}, false),
+		PropType("oneshot"),
+		PropRemainAfterExit(true),
+	}
+
+	timer, serviceName, job, err := conn.StartTransientTimer(t.Context(), "", "replace", []Property{onActive, accuracy}, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+	defer func() {
+		_ = runStopUnit(t, conn, TrUnitProp{timer, nil})
+		_ = runStopUnit(t, conn, TrUnitProp{serviceName, nil})
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := GetProperty[string](t.Context(), conn, serviceName, "Unit", "ActiveState")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state == "active" {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("%s was not activated by %s", serviceName, timer)
+}
+
+// TestStartTransientPathUnit starts a transient path unit and ensures that it
+// activates its service once the watched file appears.
+func TestStartTransientPathUnit(t *testing.T) {
+	conn := setupConn(t)
+
+	watched := filepath.Join(t.TempDir(), "trigger")
+	exists, err := PropPathExists(watched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := []Property{
+		PropExecStart([]string{"/bin/true"}, false),
+		PropType("oneshot"),
+		PropRemainAfterExit(true),
+	}
+
+	name := fmt.Sprintf("testing-path-%d", time.Now().UnixNano())
+	pathUnit, serviceName, job, err := conn.StartTransientPathUnit(t.Context(), name, "replace", []Property{exists}, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pathUnit != name+".path" || serviceName != name+".service" {
+		t.Fatalf("unexpected unit names %q, %q", pathUnit, serviceName)
+	}
+	if err := job.Wait(t.Context()); err != nil {
+		t.Fatal("Job is not done:", err)
+	}
+	defer func() {
+		_ = runStopUnit(t, conn, TrUnitProp{pathUnit, nil})
+		_ = runStopUnit(t, conn, TrUnitProp{serviceName, nil})
+	}()
+
+	if err := os.WriteFile(watched, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := GetProperty[string](t.Context(), conn, serviceName, "Unit", "ActiveState")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state == "active" {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("%s was not activated by %s", serviceName, pathUnit)
+}
@@ -0,0 +1,502 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// DurationInfinity is the value that durations decoded from systemd
+// properties take when systemd reports them as infinite (USEC_INFINITY).
+const DurationInfinity = time.Duration(math.MaxInt64)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// UnitProperties holds the properties of the org.freedesktop.systemd1.Unit
+// interface, which are common to all unit types. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Properties1
+//
+// Realtime timestamps are converted to time.Time and are zero if the event
+// did not happen yet. Monotonic timestamps are converted to the
+// time.Duration elapsed since boot.
+type UnitProperties struct {
+	Id                   string
+	Names                []string
+	Following            string
+	Requires             []string
+	Requisite            []string
+	Wants                []string
+	BindsTo              []string
+	PartOf               []string
+	RequiredBy           []string
+	RequisiteOf          []string
+	WantedBy             []string
+	BoundBy              []string
+	ConsistsOf           []string
+	Conflicts            []string
+	ConflictedBy         []string
+	Before               []string
+	After                []string
+	OnFailure            []string
+	Triggers             []string
+	TriggeredBy          []string
+	PropagatesReloadTo   []string
+	ReloadPropagatedFrom []string
+	JoinsNamespaceOf     []string
+	RequiresMountsFor    []string
+	Documentation        []string
+	Description          string
+	LoadState            string
+	ActiveState          string
+	FreezerState         string
+	SubState             string
+	FragmentPath         string
+	SourcePath           string
+	DropInPaths          []string
+	UnitFileState        string
+	UnitFilePreset       string
+
+	StateChangeTimestamp            time.Time
+	StateChangeTimestampMonotonic   time.Duration
+	InactiveExitTimestamp           time.Time
+	InactiveExitTimestampMonotonic  time.Duration
+	ActiveEnterTimestamp            time.Time
+	ActiveEnterTimestampMonotonic   time.Duration
+	ActiveExitTimestamp             time.Time
+	ActiveExitTimestampMonotonic    time.Duration
+	InactiveEnterTimestamp          time.Time
+	InactiveEnterTimestampMonotonic time.Duration
+	ConditionTimestamp              time.Time
+	ConditionTimestampMonotonic     time.Duration
+	AssertTimestamp                 time.Time
+	AssertTimestampMonotonic        time.Duration
+	JobTimeoutUSec                  time.Duration
+	JobRunningTimeoutUSec           time.Duration
+	CanStart                        bool
+	CanStop                         bool
+	CanReload                       bool
+	CanIsolate                      bool
+	CanFreeze                       bool
+	StopWhenUnneeded                bool
+	RefuseManualStart               bool
+	RefuseManualStop                bool
+	AllowIsolate                    bool
+	DefaultDependencies             bool
+	IgnoreOnIsolate                 bool
+	NeedDaemonReload                bool
+	ConditionResult                 bool
+	AssertResult                    bool
+	Transient                       bool
+	Perpetual                       bool
+	OnFailureJobMode                string
+	CollectMode                     string
+	InvocationID                    []byte
+	Markers                         []string
+}
+
+// ExecCommand describes one command line of a service's Exec*= setting
+// together with the runtime information of its last invocation.
+type ExecCommand struct {
+	Path                    string        // the binary path to execute
+	Args                    []string      // the arguments, starting with argument 0
+	IgnoreErrors            bool          // whether a failure of the command is ignored
+	StartTimestamp          time.Time     // when the command was last started
+	StartTimestampMonotonic time.Duration // when the command was last started, since boot
+	ExitTimestamp           time.Time     // when the command last exited
+	ExitTimestampMonotonic  time.Duration // when the command last exited, since boot
+	PID                     uint32        // the PID of the last invocation
+	Code                    int32         // the SIGCHLD code of the last invocation (CLD_EXITED, CLD_KILLED, ...)
+	Status                  int32         // the exit status or signal number of the last invocation
+}
+
+// ServiceProperties holds the properties of the
+// org.freedesktop.systemd1.Service interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Service_Unit_Objects
+type ServiceProperties struct {
+	Type                            string
+	ExitType                        string
+	Restart                         string
+	PIDFile                         string
+	NotifyAccess                    string
+	RestartUSec                     time.Duration
+	TimeoutStartUSec                time.Duration
+	TimeoutStopUSec                 time.Duration
+	TimeoutAbortUSec                time.Duration
+	RuntimeMaxUSec                  time.Duration
+	WatchdogUSec                    time.Duration
+	WatchdogTimestamp               time.Time
+	WatchdogTimestampMonotonic      time.Duration
+	RemainAfterExit                 bool
+	GuessMainPID                    bool
+	MainPID                         uint32
+	ControlPID                      uint32
+	BusName                         string
+	StatusText                      string
+	StatusErrno                     int32
+	Result                          string
+	ReloadResult                    string
+	CleanResult                     string
+	NRestarts                       uint32
+	OOMPolicy                       string
+	ExecMainStartTimestamp          time.Time
+	ExecMainStartTimestampMonotonic time.Duration
+	ExecMainExitTimestamp           time.Time
+	ExecMainExitTimestampMonotonic  time.Duration
+	ExecMainPID                     uint32
+	ExecMainCode                    int32
+	ExecMainStatus                  int32
+	ExecCondition                   []ExecCommand
+	ExecStartPre                    []ExecCommand
+	ExecStart                       []ExecCommand
+	ExecStartPost                   []ExecCommand
+	ExecReload                      []ExecCommand
+	ExecStop                        []ExecCommand
+	ExecStopPost                    []ExecCommand
+	Slice                           string
+	ControlGroup                    string
+	MemoryCurrent                   uint64
+	MemoryPeak                      uint64
+	CPUUsageNSec                    uint64
+	TasksCurrent                    uint64
+	IPIngressBytes                  uint64
+	IPEgressBytes                   uint64
+	IOReadBytes                     uint64
+	IOWriteBytes                    uint64
+	User                            string
+	Group                           string
+	DynamicUser                     bool
+	WorkingDirectory                string
+	Environment                     []string
+}
+
+// SocketListen is a single listening address of a socket unit.
+type SocketListen struct {
+	Type    string // the type of the address, e.g. Stream, Datagram or FIFO
+	Address string // the address itself
+}
+
+// SocketProperties holds the properties of the
+// org.freedesktop.systemd1.Socket interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Socket_Unit_Objects
+type SocketProperties struct {
+	Listen             []SocketListen
+	BindIPv6Only       string
+	Backlog            uint32
+	TimeoutUSec        time.Duration
+	Accept             bool
+	FlushPending       bool
+	Writable           bool
+	KeepAlive          bool
+	NoDelay            bool
+	SocketMode         uint32
+	DirectoryMode      uint32
+	MaxConnections     uint32
+	NConnections       uint32
+	NAccepted          uint32
+	NRefused           uint32
+	FileDescriptorName string
+	ControlPID         uint32
+	Result             string
+	Slice              string
+	ControlGroup       string
+	MemoryCurrent      uint64
+	CPUUsageNSec       uint64
+	TasksCurrent       uint64
+}
+
+// TimerMonotonic is a single monotonic trigger of a timer unit.
+type TimerMonotonic struct {
+	Base       string        // the kind of timer, e.g. OnActiveUSec or OnBootUSec
+	Value      time.Duration // the configured offset
+	NextElapse time.Duration // the next elapse point, on the monotonic clock
+}
+
+// TimerCalendar is a single calendar trigger of a timer unit.
+type TimerCalendar struct {
+	Base       string    // the kind of timer, always OnCalendar
+	Expression string    // the calendar specification
+	NextElapse time.Time // the next elapse point
+}
+
+// TimerProperties holds the properties of the org.freedesktop.systemd1.Timer
+// interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Timer_Unit_Objects
+type TimerProperties struct {
+	Unit                     string
+	TimersMonotonic          []TimerMonotonic
+	TimersCalendar           []TimerCalendar
+	OnClockChange            bool
+	OnTimezoneChange         bool
+	NextElapseUSecRealtime   time.Time
+	NextElapseUSecMonotonic  time.Duration
+	LastTriggerUSec          time.Time
+	LastTriggerUSecMonotonic time.Duration
+	Result                   string
+	AccuracyUSec             time.Duration
+	RandomizedDelayUSec      time.Duration
+	FixedRandomDelay         bool
+	Persistent               bool
+	WakeSystem               bool
+	RemainAfterElapse        bool
+}
+
+// MountProperties holds the properties of the org.freedesktop.systemd1.Mount
+// interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Mount_Unit_Objects
+type MountProperties struct {
+	Where         string
+	What          string
+	Options       string
+	Type          string
+	TimeoutUSec   time.Duration
+	ControlPID    uint32
+	DirectoryMode uint32
+	SloppyOptions bool
+	LazyUnmount   bool
+	ForceUnmount  bool
+	ReadWriteOnly bool
+	Result        string
+	UID           uint32
+	GID           uint32
+	Slice         string
+	ControlGroup  string
+	MemoryCurrent uint64
+	CPUUsageNSec  uint64
+	TasksCurrent  uint64
+}
+
+// SliceProperties holds the properties of the org.freedesktop.systemd1.Slice
+// interface. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Slice_Unit_Objects
+type SliceProperties struct {
+	Slice          string
+	ControlGroup   string
+	MemoryCurrent  uint64
+	MemoryPeak     uint64
+	CPUUsageNSec   uint64
+	TasksCurrent   uint64
+	IPIngressBytes uint64
+	IPEgressBytes  uint64
+	IOReadBytes    uint64
+	IOWriteBytes   uint64
+	CPUWeight      uint64
+	MemoryMax      uint64
+	TasksMax       uint64
+}
+
+// decodeProperties stores the D-Bus properties in props into the struct
+// pointed to by out. Fields are matched by name; properties without a
+// matching field and fields without a matching property are left alone, so
+// that the same struct works across systemd versions.
+func decodeProperties(props map[string]dbus.Variant, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("dbus: decode target must be a pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		prop, ok := props[field.Name]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(prop.Value(), v.Field(i)); err != nil {
+			return fmt.Errorf("dbus: property %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// decodeValue stores a single D-Bus value into dst, converting microsecond
+// timestamps and durations into time.Time and time.Duration.
+func decodeValue(src any, dst reflect.Value) error {
+	switch {
+	case dst.Type() == timeType:
+		usec, ok := src.(uint64)
+		if !ok {
+			return fmt.Errorf("cannot store %T in time.Time", src)
+		}
+		dst.Set(reflect.ValueOf(usecToTime(usec)))
+		return nil
+	case dst.Type() == durationType:
+		usec, ok := src.(uint64)
+		if !ok {
+			return fmt.Errorf("cannot store %T in time.Duration", src)
+		}
+		dst.Set(reflect.ValueOf(usecToDuration(usec)))
+		return nil
+	case dst.Kind() == reflect.Struct:
+		fields, ok := src.([]any)
+		if !ok || len(fields) != dst.NumField() {
+			return fmt.Errorf("cannot store %T in %s", src, dst.Type())
+		}
+		for i := range fields {
+			if err := decodeValue(fields[i], dst.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Struct:
+		sv := reflect.ValueOf(src)
+		if sv.Kind() != reflect.Slice {
+			return fmt.Errorf("cannot store %T in %s", src, dst.Type())
+		}
+		out := reflect.MakeSlice(dst.Type(), sv.Len(), sv.Len())
+		for i := range sv.Len() {
+			if err := decodeValue(sv.Index(i).Interface(), out.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	}
+
+	return dbus.Store([]any{src}, dst.Addr().Interface())
+}
+
+// usecToTime converts a systemd realtime timestamp in microseconds to a
+// time.Time. Zero, which systemd uses for events that never happened, maps
+// to the zero time.Time.
+func usecToTime(usec uint64) time.Time {
+	if usec == 0 || usec == math.MaxUint64 {
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(usec))
+}
+
+// usecToDuration converts a systemd duration in microseconds to a
+// time.Duration. USEC_INFINITY and values too large to be represented map to
+// DurationInfinity.
+func usecToDuration(usec uint64) time.Duration {
+	if usec > uint64(math.MaxInt64/int64(time.Microsecond)) {
+		return DurationInfinity
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// getTypedProperties fetches all properties of the given interface of a unit
+// and decodes them into out.
+func (c *Conn) getTypedProperties(ctx context.Context, unit string, dbusInterface string, out any) error {
+	if err := checkUnitName(unit); err != nil {
+		return err
+	}
+
+	path := unitPath(unit)
+
+	var props map[string]dbus.Variant
+	obj := c.object(path)
+	err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, dbusInterface).Store(&props)
+	if err != nil {
+		return err
+	}
+
+	return decodeProperties(props, out)
+}
+
+// GetTypedUnitProperties takes the (unescaped) unit name and returns the
+// properties common to all unit types. Use [Conn.GetUnitPropertiesContext]
+// for properties not covered by [UnitProperties].
+func (c *Conn) GetTypedUnitProperties(ctx context.Context, unit string) (*UnitProperties, error) {
+	var props UnitProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Unit", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetTypedServiceProperties returns the service specific properties of a
+// service unit.
+func (c *Conn) GetTypedServiceProperties(ctx context.Context, unit string) (*ServiceProperties, error) {
+	var props ServiceProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Service", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetTypedSocketProperties returns the socket specific properties of a
+// socket unit.
+func (c *Conn) GetTypedSocketProperties(ctx context.Context, unit string) (*SocketProperties, error) {
+	var props SocketProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Socket", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetTypedTimerProperties returns the timer specific properties of a timer
+// unit.
+func (c *Conn) GetTypedTimerProperties(ctx context.Context, unit string) (*TimerProperties, error) {
+	var props TimerProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Timer", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetTypedMountProperties returns the mount specific properties of a mount
+// unit.
+func (c *Conn) GetTypedMountProperties(ctx context.Context, unit string) (*MountProperties, error) {
+	var props MountProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Mount", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetTypedSliceProperties returns the slice specific properties of a slice
+// unit.
+func (c *Conn) GetTypedSliceProperties(ctx context.Context, unit string) (*SliceProperties, error) {
+	var props SliceProperties
+	if err := c.getTypedProperties(ctx, unit, "org.freedesktop.systemd1.Slice", &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// GetProperty returns a single property of a unit, converted to T. For valid
+// values of unitType, see [Conn.GetUnitTypePropertiesContext]. Timestamps and
+// durations reported in microseconds may be requested as time.Time and
+// time.Duration respectively.
+func GetProperty[T any](ctx context.Context, c *Conn, unit string, unitType string, propertyName string) (T, error) {
+	var value T
+
+	prop, err := c.getProperty(ctx, unit, "org.freedesktop.systemd1."+unitType, propertyName)
+	if err != nil {
+		return value, err
+	}
+
+	if err := decodeValue(prop.Value.Value(), reflect.ValueOf(&value).Elem()); err != nil {
+		return value, fmt.Errorf("dbus: property %s: %w", propertyName, err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestDecodeProperties(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"Id":                            dbus.MakeVariant("foo.service"),
+		"Names":                         dbus.MakeVariant([]string{"foo.service", "bar.service"}),
+		"ActiveState":                   dbus.MakeVariant("active"),
+		"ActiveEnterTimestamp":          dbus.MakeVariant(uint64(1700000000123456)),
+		"ActiveEnterTimestampMonotonic": dbus.MakeVariant(uint64(2500000)),
+		"InactiveExitTimestamp":         dbus.MakeVariant(uint64(0)),
+		"JobTimeoutUSec":                dbus.MakeVariant(uint64(math.MaxUint64)),
+		"CanStart":                      dbus.MakeVariant(true),
+		"InvocationID":                  dbus.MakeVariant([]byte{1, 2, 3}),
+		"SomethingNew":                  dbus.MakeVariant("ignored"),
+	}
+
+	var got UnitProperties
+	if err := decodeProperties(props, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := UnitProperties{
+		Id:                            "foo.service",
+		Names:                         []string{"foo.service", "bar.service"},
+		ActiveState:                   "active",
+		ActiveEnterTimestamp:          time.UnixMicro(1700000000123456),
+		ActiveEnterTimestampMonotonic: 2500 * time.Millisecond,
+		JobTimeoutUSec:                DurationInfinity,
+		CanStart:                      true,
+		InvocationID:                  []byte{1, 2, 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeProperties returned %+v, want %+v", got, want)
+	}
+	if !got.InactiveExitTimestamp.IsZero() {
+		t.Errorf("zero timestamp decoded as %v, want zero time", got.InactiveExitTimestamp)
+	}
+}
+
+func TestDecodePropertiesNested(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"ExecMainStatus": dbus.MakeVariant(int32(3)),
+		"ExecStart": dbus.MakeVariant([][]any{{
+			"/bin/true", []string{"/bin/true", "-x"}, false,
+			uint64(1700000000000000), uint64(1000000), uint64(0), uint64(0),
+			uint32(42), int32(1), int32(0),
+		}}),
+	}
+
+	var got ServiceProperties
+	if err := decodeProperties(props, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.ExecMainStatus != 3 {
+		t.Errorf("ExecMainStatus = %d, want 3", got.ExecMainStatus)
+	}
+	want := []ExecCommand{{
+		Path:                    "/bin/true",
+		Args:                    []string{"/bin/true", "-x"},
+		StartTimestamp:          time.UnixMicro(1700000000000000),
+		StartTimestampMonotonic: time.Second,
+		PID:                     42,
+		Code:                    1,
+	}}
+	if !reflect.DeepEqual(got.ExecStart, want) {
+		t.Errorf("ExecStart = %+v, want %+v", got.ExecStart, want)
+	}
+}
+
+func TestDecodePropertiesMismatch(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"MainPID": dbus.MakeVariant("not a number"),
+	}
+
+	var got ServiceProperties
+	if err := decodeProperties(props, &got); err == nil {
+		t.Fatal("expected an error when decoding a string into MainPID")
+	}
+
+	if err := decodeProperties(props, got); err == nil {
+		t.Fatal("expected an error when decoding into a non-pointer")
+	}
+}
+
+func TestGetTypedUnitProperties(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	reschan := make(chan string)
+	_, err := conn.StartUnit(target, "replace", reschan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := <-reschan
+	if job != "done" {
+		t.Fatal("Job is not done:", job)
+	}
+	defer func() {
+		if err := runStopUnit(t, conn, TrUnitProp{target, nil}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	props, err := conn.GetTypedUnitProperties(t.Context(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.Id != target {
+		t.Fatalf("Id = %q, want %q", props.Id, target)
+	}
+	if props.ActiveState != "active" {
+		t.Fatalf("ActiveState = %q, want active", props.ActiveState)
+	}
+	if props.ActiveEnterTimestamp.IsZero() {
+		t.Fatal("ActiveEnterTimestamp should be set for an active unit")
+	}
+
+	service, err := conn.GetTypedServiceProperties(t.Context(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.MainPID == 0 {
+		t.Fatal("MainPID should be set for a running service")
+	}
+
+	pid, err := GetProperty[uint32](t.Context(), conn, target, "Service", "MainPID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid != service.MainPID {
+		t.Fatalf("GetProperty returned MainPID %d, want %d", pid, service.MainPID)
+	}
+
+	enter, err := GetProperty[time.Time](t.Context(), conn, target, "Unit", "ActiveEnterTimestamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enter.Equal(props.ActiveEnterTimestamp) {
+		t.Fatalf("GetProperty returned %v, want %v", enter, props.ActiveEnterTimestamp)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbuserr maps the D-Bus errors of systemd services to sentinel
+// errors. Its Error type is re-exported by the dbus package.
+package dbuserr
+
+import (
+	"errors"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Error is a D-Bus error returned by a service that matches a sentinel error
+// of the package making the call.
+type Error struct {
+	Name    string // The D-Bus error name
+	Message string // The error message of the service
+
+	sentinel error
+	err      error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Name
+}
+
+// Is matches the sentinel error for the D-Bus error name.
+func (e *Error) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// Unwrap returns the underlying dbus.Error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// As returns the D-Bus error in the chain of err. godbus returns dbus.Error
+// values for error replies, but *dbus.Error is accepted as well.
+func As(err error) (dbus.Error, bool) {
+	if ptr := (*dbus.Error)(nil); errors.As(err, &ptr) {
+		return *ptr, true
+	}
+	var dbusErr dbus.Error
+	return dbusErr, errors.As(err, &dbusErr)
+}
+
+// Message returns the error message of a D-Bus error, the first string of its
+// body.
+func Message(dbusErr dbus.Error) string {
+	if len(dbusErr.Body) > 0 {
+		msg, _ := dbusErr.Body[0].(string)
+		return msg
+	}
+	return ""
+}
+
+// Wrap returns an *Error for the D-Bus errors named in sentinels, and err
+// otherwise.
+func Wrap(err error, sentinels map[string]error) error {
+	dbusErr, ok := As(err)
+	if !ok {
+		return err
+	}
+	sentinel, ok := sentinels[dbusErr.Name]
+	if !ok {
+		return err
+	}
+	return &Error{
+		Name:     dbusErr.Name,
+		Message:  Message(dbusErr),
+		sentinel: sentinel,
+		err:      err,
+	}
+}
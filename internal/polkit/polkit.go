@@ -0,0 +1,134 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package polkit implements the interactive authorization support shared by
+// the dbus, login1 and machine1 packages. Its exported names are re-exported
+// by the dbus package.
+package polkit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/coreos/go-systemd/v22/internal/dbuserr"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	errAccessDenied                     = "org.freedesktop.DBus.Error.AccessDenied"
+	errInteractiveAuthorizationRequired = "org.freedesktop.DBus.Error.InteractiveAuthorizationRequired"
+)
+
+var (
+	// ErrAccessDenied matches all authorization errors.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInteractiveAuthorizationRequired matches authorization errors that
+	// interactive authorization may resolve.
+	ErrInteractiveAuthorizationRequired = errors.New("interactive authorization required")
+)
+
+type contextKey struct{}
+
+// WithInteractive returns a context that allows or forbids interactive
+// authorization for the calls made with it, overriding the connection
+// setting.
+func WithInteractive(ctx context.Context, allow bool) context.Context {
+	return context.WithValue(ctx, contextKey{}, allow)
+}
+
+// Flags returns the message flags for a call made with ctx on a connection
+// that allows interactive authorization if conn is true.
+func Flags(ctx context.Context, conn bool) dbus.Flags {
+	if allow, ok := ctx.Value(contextKey{}).(bool); ok {
+		conn = allow
+	}
+	if conn {
+		return dbus.FlagAllowInteractiveAuthorization
+	}
+	return 0
+}
+
+// Error is an authorization error returned by a D-Bus service.
+type Error struct {
+	Name    string // The D-Bus error name
+	Message string // The error message of the service
+
+	// Interactive is set if the call may succeed when retried with
+	// interactive authorization allowed, so that the user can be asked to
+	// authenticate.
+	Interactive bool
+
+	err error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Name
+}
+
+// Is matches ErrAccessDenied, and ErrInteractiveAuthorizationRequired if
+// interactive authorization may resolve the error.
+func (e *Error) Is(target error) bool {
+	return target == ErrAccessDenied || target == ErrInteractiveAuthorizationRequired && e.Interactive
+}
+
+// Unwrap returns the underlying dbus.Error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Wrap returns an *Error for D-Bus authorization errors, and err otherwise.
+func Wrap(err error) error {
+	dbusErr, ok := dbuserr.As(err)
+	if !ok || dbusErr.Name != errAccessDenied && dbusErr.Name != errInteractiveAuthorizationRequired {
+		return err
+	}
+	return &Error{
+		Name:        dbusErr.Name,
+		Message:     dbuserr.Message(dbusErr),
+		Interactive: dbusErr.Name == errInteractiveAuthorizationRequired,
+		err:         err,
+	}
+}
+
+// Object wraps a D-Bus object to allow interactive authorization for its
+// method calls if the connection or the context allow it, and to return
+// authorization errors as *Error and the errors named in Errors as
+// *dbuserr.Error.
+type Object struct {
+	dbus.BusObject
+	Interactive *atomic.Bool     // The connection setting
+	Errors      map[string]error // The sentinel errors of the service
+}
+
+func (o *Object) Call(method string, flags dbus.Flags, args ...any) *dbus.Call {
+	return o.CallWithContext(context.Background(), method, flags, args...)
+}
+
+func (o *Object) CallWithContext(ctx context.Context, method string, flags dbus.Flags, args ...any) *dbus.Call {
+	call := o.BusObject.CallWithContext(ctx, method, flags|Flags(ctx, o.Interactive.Load()), args...)
+	call.Err = dbuserr.Wrap(Wrap(call.Err), o.Errors)
+	return call
+}
+
+func (o *Object) Go(method string, flags dbus.Flags, ch chan *dbus.Call, args ...any) *dbus.Call {
+	return o.GoWithContext(context.Background(), method, flags, ch, args...)
+}
+
+func (o *Object) GoWithContext(ctx context.Context, method string, flags dbus.Flags, ch chan *dbus.Call, args ...any) *dbus.Call {
+	return o.BusObject.GoWithContext(ctx, method, flags|Flags(ctx, o.Interactive.Load()), ch, args...)
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package polkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestFlags(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		ctx  context.Context
+		conn bool
+		want dbus.Flags
+	}{
+		{ctx, false, 0},
+		{ctx, true, dbus.FlagAllowInteractiveAuthorization},
+		{WithInteractive(ctx, true), false, dbus.FlagAllowInteractiveAuthorization},
+		{WithInteractive(ctx, false), true, 0},
+	}
+	for i, tt := range tests {
+		if got := Flags(tt.ctx, tt.conn); got != tt.want {
+			t.Errorf("%d: Flags() = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	denied := dbus.Error{Name: errAccessDenied, Body: []any{"Access denied"}}
+	interactive := dbus.NewError(errInteractiveAuthorizationRequired, nil)
+	other := dbus.NewError("org.freedesktop.systemd1.NoSuchUnit", []any{"Unit foo.service not found."})
+
+	tests := []struct {
+		err               error
+		auth, interactive bool
+		msg               string
+	}{
+		{denied, true, false, "Access denied"},
+		{interactive, true, true, errInteractiveAuthorizationRequired},
+		{fmt.Errorf("calling: %w", denied), true, false, "Access denied"},
+		{other, false, false, "Unit foo.service not found."},
+		{errors.New("other"), false, false, "other"},
+	}
+	for _, tt := range tests {
+		err := Wrap(tt.err)
+		if got := errors.Is(err, ErrAccessDenied); got != tt.auth {
+			t.Errorf("errors.Is(Wrap(%v), ErrAccessDenied) = %v, want %v", tt.err, got, tt.auth)
+		}
+		if got := errors.Is(err, ErrInteractiveAuthorizationRequired); got != tt.interactive {
+			t.Errorf("errors.Is(Wrap(%v), ErrInteractiveAuthorizationRequired) = %v, want %v", tt.err, got, tt.interactive)
+		}
+		if err.Error() != tt.msg {
+			t.Errorf("Wrap(%v) = %q, want %q", tt.err, err.Error(), tt.msg)
+		}
+		if tt.auth && !errors.As(err, new(*dbus.Error)) && !errors.As(err, new(dbus.Error)) {
+			t.Errorf("Wrap(%v) does not wrap the D-Bus error", tt.err)
+		}
+	}
+	if Wrap(nil) != nil {
+		t.Error("Wrap(nil) != nil")
+	}
+}
+
+// fakeObject records the flags of the last call and fails it with err.
+type fakeObject struct {
+	dbus.BusObject
+	flags dbus.Flags
+	err   error
+}
+
+func (o *fakeObject) CallWithContext(ctx context.Context, method string, flags dbus.Flags, args ...any) *dbus.Call {
+	o.flags = flags
+	return &dbus.Call{Method: method, Err: o.err}
+}
+
+func TestObject(t *testing.T) {
+	fake := &fakeObject{err: dbus.NewError(errInteractiveAuthorizationRequired, []any{"Interactive authentication required."})}
+	var interactive atomic.Bool
+	obj := &Object{BusObject: fake, Interactive: &interactive}
+
+	err := obj.Call("org.freedesktop.login1.Manager.PowerOff", dbus.FlagNoAutoStart, false).Err
+	if fake.flags != dbus.FlagNoAutoStart {
+		t.Errorf("flags = %v, want %v", fake.flags, dbus.FlagNoAutoStart)
+	}
+	var authErr *Error
+	if !errors.As(err, &authErr) || !authErr.Interactive {
+		t.Errorf("Call() error = %v, want an interactive *Error", err)
+	}
+
+	interactive.Store(true)
+	obj.Call("org.freedesktop.login1.Manager.PowerOff", 0, false)
+	if fake.flags != dbus.FlagAllowInteractiveAuthorization {
+		t.Errorf("flags = %v, want %v", fake.flags, dbus.FlagAllowInteractiveAuthorization)
+	}
+
+	obj.CallWithContext(WithInteractive(context.Background(), false), "org.freedesktop.login1.Manager.PowerOff", 0, false)
+	if fake.flags != 0 {
+		t.Errorf("flags = %v, want 0", fake.flags)
+	}
+}
@@ -22,7 +22,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
+	"github.com/coreos/go-systemd/v22/internal/polkit"
 	"github.com/godbus/dbus/v5"
 )
 
@@ -34,10 +36,27 @@ const (
 	dbusPath             = "/org/freedesktop/login1"
 )
 
+// Sentinel errors for the D-Bus errors of logind. They are returned as a
+// dbus.ServiceError and can be checked for with errors.Is.
+var (
+	ErrNoSuchSession = errors.New("login1: no such session")
+	ErrNoSuchUser    = errors.New("login1: no such user")
+)
+
+// serviceErrors maps the D-Bus error names of logind to their sentinel
+// errors.
+var serviceErrors = map[string]error{
+	"org.freedesktop.login1.NoSuchSession": ErrNoSuchSession,
+	"org.freedesktop.login1.NoSuchUser":    ErrNoSuchUser,
+}
+
 // Conn is a connection to systemds dbus endpoint.
 type Conn struct {
 	conn   *dbus.Conn
 	object dbus.BusObject
+
+	// interactive allows interactive authorization for all calls.
+	interactive atomic.Bool
 }
 
 // New establishes a connection to the system bus and authenticates.
@@ -67,6 +86,14 @@ func (c *Conn) Connected() bool {
 	return c.conn.Connected()
 }
 
+// SetInteractiveAuthorization allows or forbids interactive authorization for
+// all calls on the connection. It is forbidden by default. Calls denied by
+// polkit fail with a dbus.AuthorizationError. Methods taking a context can
+// override it with dbus.WithInteractiveAuthorization.
+func (c *Conn) SetInteractiveAuthorization(allow bool) {
+	c.interactive.Store(allow)
+}
+
 func (c *Conn) initConnection() error {
 	var err error
 	c.conn, err = dbus.SystemBusPrivate()
@@ -91,11 +118,22 @@ func (c *Conn) initConnection() error {
 		return err
 	}
 
-	c.object = c.conn.Object("org.freedesktop.login1", dbus.ObjectPath(dbusPath))
+	c.object = c.objectAt(dbusPath)
 
 	return nil
 }
 
+// objectAt returns the logind object at path. Its method calls allow
+// interactive authorization if enabled, and return the errors of logind as
+// dbus.AuthorizationError and dbus.ServiceError.
+func (c *Conn) objectAt(path dbus.ObjectPath) dbus.BusObject {
+	return &polkit.Object{
+		BusObject:   c.conn.Object(dbusDest, path),
+		Interactive: &c.interactive,
+		Errors:      serviceErrors,
+	}
+}
+
 // Session object definition.
 type Session struct {
 	ID   string
@@ -167,8 +205,7 @@ func (c *Conn) GetActiveSession() (dbus.ObjectPath, error) {
 		return "", err
 	}
 
-	seat0Obj := c.conn.Object(dbusDest, seat0Path)
-	activeSession, err := seat0Obj.GetProperty(dbusDest + ".Seat.ActiveSession")
+	activeSession, err := c.getProperty(context.Background(), seat0Path, dbusDest+".Seat", "ActiveSession")
 	if err != nil {
 		return "", err
 	}
@@ -190,13 +227,12 @@ func (c *Conn) GetSessionUser(sessionPath dbus.ObjectPath) (*User, error) {
 		return nil, errors.New("empty sessionPath")
 	}
 
-	activeSessionObj := c.conn.Object(dbusDest, sessionPath)
-	sessionUserName, err := activeSessionObj.GetProperty(dbusDest + ".Session.Name")
+	sessionUserName, err := c.getProperty(context.Background(), sessionPath, dbusSessionInterface, "Name")
 	if err != nil {
 		return nil, err
 	}
 
-	sessionUser, err := activeSessionObj.GetProperty(dbusDest + ".Session.User")
+	sessionUser, err := c.getProperty(context.Background(), sessionPath, dbusSessionInterface, "User")
 	if err != nil {
 		return nil, err
 	}
@@ -227,8 +263,7 @@ func (c *Conn) GetSessionDisplay(sessionPath dbus.ObjectPath) (string, error) {
 	if len(sessionPath) == 0 {
 		return "", errors.New("empty sessionPath")
 	}
-	sessionObj := c.conn.Object(dbusDest, sessionPath)
-	display, err := sessionObj.GetProperty(dbusDest + ".Session.Display")
+	display, err := c.getProperty(context.Background(), sessionPath, dbusSessionInterface, "Display")
 	if err != nil {
 		return "", err
 	}
@@ -375,7 +410,7 @@ func (c *Conn) getProperties(ctx context.Context, path dbus.ObjectPath, dbusInte
 		return nil, fmt.Errorf("invalid object path (%s)", path)
 	}
 
-	obj := c.conn.Object(dbusDest, path)
+	obj := c.objectAt(path)
 
 	var props map[string]dbus.Variant
 	err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, dbusInterface).Store(&props)
@@ -391,7 +426,7 @@ func (c *Conn) getProperty(ctx context.Context, path dbus.ObjectPath, dbusInterf
 		return nil, fmt.Errorf("invalid object path (%s)", path)
 	}
 
-	obj := c.conn.Object(dbusDest, path)
+	obj := c.objectAt(path)
 
 	var prop dbus.Variant
 	err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface, property).Store(&prop)
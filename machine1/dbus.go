@@ -19,14 +19,17 @@ limitations under the License.
 package machine1
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/godbus/dbus/v5"
 
 	sd_dbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/internal/polkit"
 )
 
 const (
@@ -34,10 +37,27 @@ const (
 	dbusPath      = "/org/freedesktop/machine1"
 )
 
+// Sentinel errors for the D-Bus errors of machined. They are returned as a
+// dbus.ServiceError and can be checked for with errors.Is.
+var (
+	ErrNoSuchMachine = errors.New("machine1: no such machine")
+	ErrNoSuchImage   = errors.New("machine1: no such image")
+)
+
+// serviceErrors maps the D-Bus error names of machined to their sentinel
+// errors.
+var serviceErrors = map[string]error{
+	"org.freedesktop.machine1.NoSuchMachine": ErrNoSuchMachine,
+	"org.freedesktop.machine1.NoSuchImage":   ErrNoSuchImage,
+}
+
 // Conn is a connection to systemds dbus endpoint.
 type Conn struct {
 	conn   *dbus.Conn
 	object dbus.BusObject
+
+	// interactive allows interactive authorization for all calls.
+	interactive atomic.Bool
 }
 
 // MachineStatus is a set of necessary info for each machine
@@ -94,11 +114,22 @@ func (c *Conn) initConnection() error {
 		return err
 	}
 
-	c.object = c.conn.Object("org.freedesktop.machine1", dbus.ObjectPath(dbusPath))
+	c.object = c.objectAt(dbusPath)
 
 	return nil
 }
 
+// objectAt returns the machined object at path. Its method calls allow
+// interactive authorization if enabled, and return the errors of machined as
+// dbus.AuthorizationError and dbus.ServiceError.
+func (c *Conn) objectAt(path dbus.ObjectPath) dbus.BusObject {
+	return &polkit.Object{
+		BusObject:   c.conn.Object("org.freedesktop.machine1", path),
+		Interactive: &c.interactive,
+		Errors:      serviceErrors,
+	}
+}
+
 func (c *Conn) getPath(method string, args ...any) (dbus.ObjectPath, error) {
 	result := c.object.Call(fmt.Sprintf("%s.%s", dbusInterface, method), 0, args...)
 	if result.Err != nil {
@@ -123,6 +154,13 @@ func (c *Conn) Connected() bool {
 	return c.conn.Connected()
 }
 
+// SetInteractiveAuthorization allows or forbids interactive authorization for
+// all calls on the connection. It is forbidden by default. Calls denied by
+// polkit fail with a dbus.AuthorizationError.
+func (c *Conn) SetInteractiveAuthorization(allow bool) {
+	c.interactive.Store(allow)
+}
+
 // CreateMachine creates a new virtual machine or container with systemd-machined, generating a scope unit for it
 func (c *Conn) CreateMachine(name string, id []byte, service string, class string, pid int, root_directory string, scope_properties []sd_dbus.Property) error {
 	return c.object.Call(dbusInterface+".CreateMachine", 0, name, id, service, class, uint32(pid), root_directory, scope_properties).Err
@@ -160,7 +198,7 @@ func (c *Conn) DescribeMachine(name string) (machineProps map[string]any, err er
 	if pathErr != nil {
 		return nil, pathErr
 	}
-	obj := c.conn.Object("org.freedesktop.machine1", path)
+	obj := c.objectAt(path)
 	err = obj.Call("org.freedesktop.DBus.Properties.GetAll", 0, "").Store(&dbusProps)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,301 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NameMax is the maximum length of a unit name.
+const NameMax = 255
+
+// ErrInvalidName is wrapped by the errors returned for malformed unit names.
+var ErrInvalidName = errors.New("invalid unit name")
+
+// unitTypes are the suffixes of the unit types, without the dot.
+var unitTypes = map[string]bool{
+	"service":   true,
+	"mount":     true,
+	"swap":      true,
+	"socket":    true,
+	"target":    true,
+	"device":    true,
+	"automount": true,
+	"timer":     true,
+	"path":      true,
+	"slice":     true,
+	"scope":     true,
+}
+
+// templateTypes are the unit types that may be templated.
+var templateTypes = map[string]bool{
+	"service": true,
+	"socket":  true,
+	"target":  true,
+	"timer":   true,
+	"path":    true,
+}
+
+// pathTypes are the unit types whose names are escaped paths.
+var pathTypes = map[string]bool{
+	"mount":     true,
+	"automount": true,
+	"swap":      true,
+	"device":    true,
+}
+
+// Name is a unit name, such as "foo.service", the template "foo@.service" or
+// its instance "foo@bar.service".
+type Name string
+
+// Parse parses and validates a unit name. In addition to the checks of
+// [Name.Validate], only some types may be templated, and the names of mount,
+// automount, swap and device units must be escaped normalized paths, as
+// produced by [NameFromPath].
+func Parse(s string) (Name, error) {
+	n := Name(s)
+	if err := n.Validate(); err != nil {
+		return "", err
+	}
+	if _, _, t, templated := n.split(); templated && !templateTypes[t] {
+		return "", invalidName(n, "%s units cannot be templated", t)
+	}
+	if pathTypes[n.Type()] && !pathNormalized(UnitNamePathUnescape(n.Prefix())) {
+		return "", invalidName(n, "not an escaped normalized path")
+	}
+	return n, nil
+}
+
+func invalidName(n Name, format string, args ...any) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalidName, string(n), fmt.Sprintf(format, args...))
+}
+
+// validChar returns whether c may be used in the prefix and instance of a
+// unit name.
+func validChar(c byte) bool {
+	return strings.IndexByte(allowed, c) != -1 || c == '-' || c == '\\'
+}
+
+// Validate checks n against the rules systemd applies to unit names: a
+// name is at most NameMax characters long, has a known type suffix and a
+// non-empty prefix made of ASCII letters, digits and ":-_.\". An instance
+// follows an '@' and may also contain '@'.
+//
+// Validate only checks the syntax, like systemd does for names passed over
+// D-Bus. Use [Parse] to also check which types may be templated and that path
+// units are named after normalized paths.
+func (n Name) Validate() error {
+	if n == "" {
+		return invalidName(n, "empty")
+	}
+	if len(n) > NameMax {
+		return invalidName(n, "longer than %d characters", NameMax)
+	}
+
+	dot := strings.LastIndexByte(string(n), '.')
+	if dot == -1 {
+		return invalidName(n, "no type suffix")
+	}
+	t := string(n[dot+1:])
+	if !unitTypes[t] {
+		return invalidName(n, "unknown type %q", t)
+	}
+
+	prefix, instance, _ := strings.Cut(string(n[:dot]), "@")
+	if prefix == "" {
+		return invalidName(n, "empty prefix")
+	}
+	for i := 0; i < len(prefix); i++ {
+		if !validChar(prefix[i]) {
+			return invalidName(n, "invalid character %q", prefix[i])
+		}
+	}
+	for i := 0; i < len(instance); i++ {
+		if !validChar(instance[i]) && instance[i] != '@' {
+			return invalidName(n, "invalid character %q", instance[i])
+		}
+	}
+
+	return nil
+}
+
+// pathNormalized returns whether p is an absolute path without empty, "."
+// or ".." components and without a trailing slash.
+func pathNormalized(p string) bool {
+	if p == "/" {
+		return true
+	}
+	if !strings.HasPrefix(p, "/") {
+		return false
+	}
+	for _, e := range strings.Split(p[1:], "/") {
+		if e == "" || e == "." || e == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns n as a string.
+func (n Name) String() string {
+	return string(n)
+}
+
+// split splits n into prefix, instance and type. templated is true if the
+// name contains an '@'.
+func (n Name) split() (prefix, instance, t string, templated bool) {
+	dot := strings.LastIndexByte(string(n), '.')
+	if dot == -1 {
+		dot = len(n)
+	} else {
+		t = string(n[dot+1:])
+	}
+	prefix, instance, templated = strings.Cut(string(n[:dot]), "@")
+	return prefix, instance, t, templated
+}
+
+// Type returns the type of n, such as "service" or "mount".
+func (n Name) Type() string {
+	_, _, t, _ := n.split()
+	return t
+}
+
+// Prefix returns the part of n before the '@' or, for names that are
+// neither templates nor instances, before the type suffix. For
+// foo@bar.service, it is foo.
+func (n Name) Prefix() string {
+	prefix, _, _, _ := n.split()
+	return prefix
+}
+
+// Instance returns the instance of n, e.g. bar for foo@bar.service. It is
+// empty for templates and for names that are not instances.
+func (n Name) Instance() string {
+	_, instance, _, _ := n.split()
+	return instance
+}
+
+// IsTemplate returns whether n is a template, such as foo@.service.
+func (n Name) IsTemplate() bool {
+	_, instance, _, templated := n.split()
+	return templated && instance == ""
+}
+
+// IsInstance returns whether n is an instance of a template, such as
+// foo@bar.service.
+func (n Name) IsInstance() bool {
+	_, instance, _, templated := n.split()
+	return templated && instance != ""
+}
+
+// Template returns the template n is an instance of, e.g. foo@.service for
+// foo@bar.service. Templates are returned as is. For other names, Template
+// returns the empty name.
+func (n Name) Template() Name {
+	prefix, _, t, templated := n.split()
+	if !templated {
+		return ""
+	}
+	return Name(prefix + "@." + t)
+}
+
+// WithInstance returns the instance of template n with the given instance,
+// e.g. foo@bar.service for foo@.service and bar. If n is an instance, its
+// instance is replaced. The instance is used as is: use
+// UnitNameEscape or UnitNamePathEscape to turn arbitrary strings into
+// instances, or [NewInstance] to do both like systemd-escape --template.
+func (n Name) WithInstance(instance string) (Name, error) {
+	if !n.IsTemplate() && !n.IsInstance() {
+		return "", invalidName(n, "not a template")
+	}
+	if instance == "" {
+		return "", invalidName(n, "empty instance")
+	}
+
+	prefix, _, t, _ := n.split()
+	return Parse(prefix + "@" + instance + "." + t)
+}
+
+// NewInstance escapes s and inserts it as the instance into template, like
+// systemd-escape --template does. If path is true, s is escaped as a path,
+// like with systemd-escape --path.
+func NewInstance(template Name, s string, path bool) (Name, error) {
+	if err := template.Validate(); err != nil {
+		return "", err
+	}
+
+	if path {
+		s = UnitNamePathEscape(s)
+	} else {
+		s = UnitNameEscape(s)
+	}
+	return template.WithInstance(s)
+}
+
+// Path returns the path a mount, automount, swap or device unit is named
+// after, e.g. /home for home.mount.
+func (n Name) Path() (string, error) {
+	if err := n.Validate(); err != nil {
+		return "", err
+	}
+	if !pathTypes[n.Type()] {
+		return "", invalidName(n, "%s units are not named after paths", n.Type())
+	}
+	return UnitNamePathUnescape(n.Prefix()), nil
+}
+
+// NameFromPath returns the name of the unit of type t for path, e.g.
+// home.mount for /home and mount, like systemd-escape --path --suffix does.
+func NameFromPath(path, t string) (Name, error) {
+	return Parse(UnitNamePathEscape(path) + "." + t)
+}
+
+// Mangle turns s into a valid unit name, like systemd-escape --mangle does.
+// Valid names are returned as is. Absolute paths become the name of the
+// device unit for paths below /dev, and of the mount unit otherwise. In
+// other strings, '/' is replaced by '-' and invalid characters are escaped,
+// and if s does not end in a known type suffix, "." + t is appended.
+func Mangle(s string, t string) (Name, error) {
+	if n := Name(s); n.Validate() == nil {
+		return n, nil
+	}
+
+	if strings.HasPrefix(s, "/") {
+		if strings.HasPrefix(s, "/dev/") || strings.HasPrefix(s, "/sys/") {
+			return NameFromPath(s, "device")
+		}
+		return NameFromPath(s, "mount")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '/':
+			b.WriteByte('-')
+		case !validChar(c) && c != '@':
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	m := b.String()
+	if dot := strings.LastIndexByte(m, '.'); dot == -1 || !unitTypes[m[dot+1:]] {
+		m += "." + t
+	}
+	return Parse(m)
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNameValidate(t *testing.T) {
+	valid := []string{
+		"foo.service",
+		"foo@.service",
+		"foo@bar.service",
+		"foo@bar@baz.service",
+		`system-getty@tty1.service`,
+		`foo\x2dbar:baz_1.socket`,
+		"-.mount",
+		"-.slice",
+		"home-user.mount",
+		`dev-disk-by\x2dlabel-root.device`,
+		"var-swapfile.swap",
+		strings.Repeat("a", NameMax-len(".service")) + ".service",
+	}
+	for _, s := range valid {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q) = %v", s, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"foo",
+		"foo.",
+		"foo.bar",
+		".service",
+		"@bar.service",
+		"foo bar.service",
+		"foo/bar.service",
+		"foo@bar baz.service",
+		"home@.mount",
+		"foo@bar.slice",
+		"home-.mount",
+		"home--user.mount",
+		strings.Repeat("a", NameMax-len(".service")+1) + ".service",
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidName", s, err)
+		}
+	}
+
+	// Validate only checks the syntax, so path units need not be named
+	// after normalized paths, and any type may be templated.
+	for _, s := range []string{"home-.mount", "home--user.mount", "dev-.device", "home@.mount", "foo@bar.slice"} {
+		if err := Name(s).Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v", s, err)
+		}
+	}
+}
+
+func TestNameParts(t *testing.T) {
+	tests := []struct {
+		name       Name
+		prefix     string
+		instance   string
+		typ        string
+		template   Name
+		isTemplate bool
+		isInstance bool
+	}{
+		{"foo.service", "foo", "", "service", "", false, false},
+		{"foo@.socket", "foo", "", "socket", "foo@.socket", true, false},
+		{"foo@bar@baz.timer", "foo", "bar@baz", "timer", "foo@.timer", false, true},
+		{"a.b.target", "a.b", "", "target", "", false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.name.Prefix(); got != tt.prefix {
+			t.Errorf("%s: Prefix() = %q, want %q", tt.name, got, tt.prefix)
+		}
+		if got := tt.name.Instance(); got != tt.instance {
+			t.Errorf("%s: Instance() = %q, want %q", tt.name, got, tt.instance)
+		}
+		if got := tt.name.Type(); got != tt.typ {
+			t.Errorf("%s: Type() = %q, want %q", tt.name, got, tt.typ)
+		}
+		if got := tt.name.Template(); got != tt.template {
+			t.Errorf("%s: Template() = %q, want %q", tt.name, got, tt.template)
+		}
+		if got := tt.name.IsTemplate(); got != tt.isTemplate {
+			t.Errorf("%s: IsTemplate() = %v", tt.name, got)
+		}
+		if got := tt.name.IsInstance(); got != tt.isInstance {
+			t.Errorf("%s: IsInstance() = %v", tt.name, got)
+		}
+	}
+}
+
+func TestNameWithInstance(t *testing.T) {
+	n, err := Name("getty@.service").WithInstance("tty1")
+	if err != nil || n != "getty@tty1.service" {
+		t.Errorf("WithInstance() = %q, %v", n, err)
+	}
+	n, err = n.WithInstance("tty2")
+	if err != nil || n != "getty@tty2.service" {
+		t.Errorf("WithInstance() on instance = %q, %v", n, err)
+	}
+	if _, err := Name("getty.service").WithInstance("tty1"); err == nil {
+		t.Error("WithInstance() on a plain name succeeded")
+	}
+	if _, err := Name("getty@.service").WithInstance("tty 1"); err == nil {
+		t.Error("WithInstance() with an unescaped instance succeeded")
+	}
+
+	tests := []struct {
+		template Name
+		s        string
+		path     bool
+		out      Name
+	}{
+		{"foo@.service", "bar baz", false, `foo@bar\x20baz.service`},
+		{"foo@.service", "a/b", false, "foo@a-b.service"},
+		{"fsck@.service", "/dev/sda1", true, "fsck@dev-sda1.service"},
+		{"fsck@.service", "/", true, "fsck@-.service"},
+	}
+	for _, tt := range tests {
+		if n, err := NewInstance(tt.template, tt.s, tt.path); err != nil || n != tt.out {
+			t.Errorf("NewInstance(%q, %q, %v) = %q, %v, want %q", tt.template, tt.s, tt.path, n, err, tt.out)
+		}
+	}
+}
+
+func TestNamePath(t *testing.T) {
+	n, err := NameFromPath("/home//user/", "mount")
+	if err != nil || n != "home-user.mount" {
+		t.Errorf("NameFromPath() = %q, %v", n, err)
+	}
+	if p, err := n.Path(); err != nil || p != "/home/user" {
+		t.Errorf("Path() = %q, %v", p, err)
+	}
+	if p, err := Name("-.mount").Path(); err != nil || p != "/" {
+		t.Errorf("Path() = %q, %v", p, err)
+	}
+	if _, err := Name("foo.service").Path(); err == nil {
+		t.Error("Path() of a service succeeded")
+	}
+}
+
+func TestMangle(t *testing.T) {
+	tests := []struct {
+		in  string
+		typ string
+		out Name
+	}{
+		{"foo.service", "service", "foo.service"},
+		{"foo", "service", "foo.service"},
+		{"foo", "target", "foo.target"},
+		{"foo.socket", "service", "foo.socket"},
+		{"foo bar", "service", `foo\x20bar.service`},
+		{"a/b", "service", "a-b.service"},
+		{"foo@bar", "service", "foo@bar.service"},
+		{"/home/user", "service", "home-user.mount"},
+		{"/dev/sda1", "service", "dev-sda1.device"},
+		{"föö", "service", `f\xc3\xb6\xc3\xb6.service`},
+	}
+	for _, tt := range tests {
+		if n, err := Mangle(tt.in, tt.typ); err != nil || n != tt.out {
+			t.Errorf("Mangle(%q, %q) = %q, %v, want %q", tt.in, tt.typ, n, err, tt.out)
+		}
+	}
+	if _, err := Mangle("", "service"); err == nil {
+		t.Error("Mangle() of the empty string succeeded")
+	}
+}